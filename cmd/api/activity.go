@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// GetGroupActivityHandler handles GET /v1/groups/:id/activity. It returns a page
+// of the group's activity feed, newest first, using the cursor and limit query
+// parameters, along with the unread marker and count of the user. The route is
+// restricted to the members of the group and administrators.
+func (app *application) GetGroupActivityHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := internal.ReadParamId(r)
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return
+	}
+
+	val := internal.NewValidator(r)
+
+	filters := model.ActivityQuery{
+		GroupId: id,
+		Cursor:  int64(internal.ReadQueryInt(r, val, "cursor", 0)),
+		Limit:   internal.ReadQueryInt(r, val, "limit", 20),
+	}
+
+	if errors := filters.ValidateActivityQuery(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	if _, err := app.Models.Groups.Get(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	reader, _ := internal.ContextGetActor(r.Context())
+
+	data, meta, err := app.Models.Activities.GetFeed(r.Context(), &filters, reader)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"metadata": meta,
+		"data":     data,
+	})
+}
+
+// MarkGroupActivityReadHandler handles POST /v1/groups/:id/activity/read. It moves
+// the current user's "unread since" marker to the given activity id, or to the
+// newest activity when none is given. Anonymous requests are rejected.
+func (app *application) MarkGroupActivityReadHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := internal.ReadParamId(r)
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return
+	}
+
+	reader, ok := internal.ContextGetActor(r.Context())
+	if !ok {
+		internal.UnauthorizedError(w, r, nil)
+		return
+	}
+
+	input := model.ActivityRead{}
+	if err := internal.ReadJSON(w, r, &input); err != nil {
+		internal.BadRequestError(w, r, err.Error())
+		return
+	}
+
+	val := internal.NewValidator(r)
+	val.CheckCode(input.LastReadId == nil || *input.LastReadId >= 0, "last_read_id", "query.positive_integer", i18n.Args{"field": "last_read_id"})
+	if !val.Valid() {
+		internal.BadRequestError(w, r, val.Errors)
+		return
+	}
+
+	if _, err := app.Models.Groups.Get(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	marker, err := app.Models.Activities.MarkRead(r.Context(), id, reader, input.LastReadId)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"last_read_id": marker,
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// GetGroupHistoryHandler handles GET /v1/groups/:id/history. It returns the
+// audit events recorded for the group identified by the id URL parameter,
+// newest first, to its members and administrators. The history remains
+// available to administrators after the group, and its members, are deleted.
+func (app *application) GetGroupHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := internal.ReadParamId(r)
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return
+	}
+
+	val := internal.NewValidator(r)
+
+	filters := model.AuditQuery{
+		EntityType: model.AuditEntityGroup,
+		EntityId:   id,
+		From:       internal.ReadQueryTime(r, val, "from", time.Time{}),
+		To:         internal.ReadQueryTime(r, val, "to", time.Time{}),
+		Page:       internal.ReadQueryInt(r, val, "page", 1),
+		PageSize:   internal.ReadQueryInt(r, val, "page_size", 10),
+	}
+
+	app.writeAuditEvents(w, r, val, &filters)
+}
+
+// GetAuditEventsHandler handles GET /v1/audit. It lists audit events across all
+// entities, supporting filtering by actor, entity and created_at time range.
+func (app *application) GetAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	val := internal.NewValidator(r)
+
+	filters := model.AuditQuery{
+		ActorId:    internal.ReadQueryInt(r, val, "actor_id", 0),
+		EntityType: internal.ReadQueryString(r, "entity_type", ""),
+		EntityId:   internal.ReadQueryInt(r, val, "entity_id", 0),
+		From:       internal.ReadQueryTime(r, val, "from", time.Time{}),
+		To:         internal.ReadQueryTime(r, val, "to", time.Time{}),
+		Page:       internal.ReadQueryInt(r, val, "page", 1),
+		PageSize:   internal.ReadQueryInt(r, val, "page_size", 10),
+	}
+
+	app.writeAuditEvents(w, r, val, &filters)
+}
+
+// writeAuditEvents validates filters, queries the audit log and writes the
+// paginated result.
+func (app *application) writeAuditEvents(
+	w http.ResponseWriter,
+	r *http.Request,
+	val *validation.Validator,
+	filters *model.AuditQuery,
+) {
+	if errors := filters.ValidateAuditQuery(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	data, meta, err := app.Models.Audit.GetAll(r.Context(), filters)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"metadata": meta,
+		"data":     data,
+	})
+}
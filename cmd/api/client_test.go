@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/Abdul4code/FairShare/pkg/client"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// groupColumns are the columns groups are read with.
+var groupColumns = []string{"id", "name", "currency", "description", "created_by", "created_at", "version"}
+
+// newClientTest serves the router of an application backed by a mock database
+// and returns a client of it, authenticated as user 1.
+func newClientTest(t *testing.T) (*client.Client, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+
+	server := httptest.NewServer(app.Router())
+	t.Cleanup(server.Close)
+
+	c, err := client.New(server.URL, client.WithToken(testToken(t, "1", false)), client.WithRetries(0, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return c, mock
+}
+
+// expectActivity expects a group activity to be recorded with its outbox
+// message and notification.
+func expectActivity(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`INSERT INTO activities`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, "2026-01-01T00:00:00Z"))
+	mock.ExpectQuery(`INSERT INTO outbox`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, "2026-01-01T00:00:00Z"))
+	mock.ExpectExec(`SELECT pg_notify`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestClientCreateGroup(t *testing.T) {
+	c, mock := newClientTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO groups`).
+		WithArgs("Trip", "Euro", "Lisbon", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "version"}).AddRow(3, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "create", "group", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectQuery(`INSERT INTO group_members`).
+		WithArgs(3, 1, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"joined_at"}).AddRow("2026-01-01T00:00:00Z"))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "create", "member", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectCommit()
+
+	group, err := c.CreateGroup(context.Background(), client.GroupInput{Name: "Trip", Currency: "Euro", Description: "Lisbon", CreatedBy: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.Id != 3 || group.Name != "Trip" || group.Version != 1 {
+		t.Errorf("CreateGroup = %+v, want group 3 named Trip at version 1", group)
+	}
+}
+
+func TestClientGetGroup(t *testing.T) {
+	c, mock := newClientTest(t)
+
+	mock.ExpectQuery(`FROM groups WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "Lisbon", 1, "2026-01-01T00:00:00Z", 2))
+
+	group, err := c.GetGroup(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.Id != 3 || group.Currency != "Euro" || group.Version != 2 {
+		t.Errorf("GetGroup = %+v, want group 3 in Euro at version 2", group)
+	}
+}
+
+func TestClientGroupsIteratesPages(t *testing.T) {
+	c, mock := newClientTest(t)
+
+	mock.ExpectQuery(`SELECT count\(id\) OVER\(\).+LIMIT 2 OFFSET 0`).
+		WillReturnRows(sqlmock.NewRows(append([]string{"count"}, groupColumns...)).
+			AddRow(3, 1, "A", "Euro", "", 1, "2026-01-01T00:00:00Z", 1).
+			AddRow(3, 2, "B", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectQuery(`SELECT count\(id\) OVER\(\).+LIMIT 2 OFFSET 2`).
+		WillReturnRows(sqlmock.NewRows(append([]string{"count"}, groupColumns...)).
+			AddRow(3, 3, "C", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+
+	ids := []int{}
+	for group, err := range c.Groups(context.Background(), client.ListGroupsParams{PageSize: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, group.Id)
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("Groups yielded %v, want [1 2 3]", ids)
+	}
+}
+
+func TestClientPatchGroup(t *testing.T) {
+	c, mock := newClientTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM groups WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectQuery(`FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectQuery(`UPDATE groups`).
+		WithArgs("Holiday", "Euro", "", 3, 1).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Holiday", "Euro", "", 1, "2026-01-01T00:00:00Z", 2))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "update", "group", 3, sqlmock.AnyArg(), []byte(`{"name":"Trip","version":1}`), []byte(`{"name":"Holiday","version":2}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectCommit()
+
+	name := "Holiday"
+	group, err := c.PatchGroup(context.Background(), 3, client.GroupUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.Name != "Holiday" || group.Version != 2 {
+		t.Errorf("PatchGroup = %+v, want Holiday at version 2", group)
+	}
+}
+
+func TestClientUpdateGroup(t *testing.T) {
+	c, mock := newClientTest(t)
+
+	// the current version is read with the row locked, then locked again by
+	// the update in the same transaction
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 4))
+	mock.ExpectQuery(`FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 4))
+	mock.ExpectQuery(`UPDATE groups`).
+		WithArgs("Holiday", "Euro", "", 3, 4).
+		WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Holiday", "Euro", "", 1, "2026-01-01T00:00:00Z", 5))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "update", "group", 3, sqlmock.AnyArg(), []byte(`{"name":"Trip","version":4}`), []byte(`{"name":"Holiday","version":5}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectCommit()
+
+	group, err := c.UpdateGroup(context.Background(), 3, client.GroupInput{Name: "Holiday", Currency: "Euro", CreatedBy: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if group.Name != "Holiday" || group.Version != 5 {
+		t.Errorf("UpdateGroup = %+v, want Holiday at version 5", group)
+	}
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		c, mock := newClientTest(t)
+
+		mock.ExpectQuery(`FROM groups WHERE id = \$1`).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(groupColumns))
+
+		_, err := c.GetGroup(ctx, 9)
+		if !errors.Is(err, client.ErrNotFound) {
+			t.Errorf("GetGroup error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		c, mock := newClientTest(t)
+
+		// the group changed between the read and the locked read of the update
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM groups WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 2))
+		mock.ExpectRollback()
+
+		name := "Holiday"
+		_, err := c.PatchGroup(ctx, 3, client.GroupUpdate{Name: &name})
+		if !errors.Is(err, client.ErrConflict) {
+			t.Errorf("PatchGroup error = %v, want ErrConflict", err)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		c, _ := newClientTest(t)
+
+		_, err := c.CreateGroup(ctx, client.GroupInput{Name: "Trip", Currency: "Yen", CreatedBy: 1})
+		if !errors.Is(err, client.ErrValidation) {
+			t.Fatalf("CreateGroup error = %v, want ErrValidation", err)
+		}
+
+		apiErr := &client.APIError{}
+		if !errors.As(err, &apiErr) || apiErr.Fields["currency"] == "" {
+			t.Errorf("CreateGroup error = %v, want a message for the currency field", err)
+		}
+	})
+}
+
+func TestClientRejectedToken(t *testing.T) {
+	c, _ := newClientTest(t)
+	c.Token = "forged.token.value"
+
+	_, err := c.GetGroup(context.Background(), 3)
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("GetGroup error = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// sseHeartbeatInterval is how often a comment line is written on an idle event
+// stream so proxies and clients do not time the connection out.
+const sseHeartbeatInterval = 15 * time.Second
+
+// sseBatchSize is the maximum number of activities read per database round trip
+// while streaming.
+const sseBatchSize = 100
+
+// GroupEventsHandler handles GET /v1/groups/:id/events. It streams the group's
+// activities as Server-Sent Events while the connection stays open. Each event
+// carries the activity id, so clients reconnecting with a Last-Event-ID header
+// (or last_event_id query parameter) receive everything they missed. Like
+// the WebSocket subscriptions, streams are restricted to the members of the
+// group by requireGroupMember.
+func (app *application) GroupEventsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := internal.ReadParamId(r)
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		internal.InternalServerError(w, r, errors.New("streaming is not supported by the response writer"))
+		return
+	}
+
+	if _, err := app.Models.Groups.Get(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	lastId, err := readLastEventId(r)
+	if err != nil {
+		internal.BadRequestError(w, r, map[string]string{"last_event_id": err.Error()})
+		return
+	}
+
+	// subscribe before reading the starting point so no change can slip in between.
+	sub := app.Hub.Subscribe(id)
+	defer sub.Unsubscribe()
+
+	ctx := r.Context()
+
+	if lastId < 0 {
+		lastId, err = app.Models.Activities.LatestId(ctx, id)
+		if err != nil {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", (3 * time.Second).Milliseconds())
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// deliver anything missed since Last-Event-ID straight away.
+	pending := true
+
+	for {
+		if pending {
+			activities, err := app.Models.Activities.GetSince(ctx, id, lastId, sseBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					internal.NewLogger().Log.Error().Err(err).Int("group_id", id).Msg("failed to read group events")
+				}
+				return
+			}
+
+			for _, activity := range activities {
+				if err := writeEvent(w, activity); err != nil {
+					return
+				}
+				lastId = activity.Id
+
+				// nothing more can happen to a deleted group; end the stream.
+				if activity.Type == model.ActivityGroupDeleted {
+					flusher.Flush()
+					return
+				}
+			}
+			flusher.Flush()
+
+			pending = len(activities) == sseBatchSize
+			if pending {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-app.Hub.Done():
+			return
+		case <-sub.C:
+			pending = true
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// readLastEventId returns the id of the last event a reconnecting client
+// received, or -1 when the client is not resuming a stream.
+func readLastEventId(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+
+	if value == "" {
+		return -1, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New(i18n.T(i18n.FromContext(r.Context()), "query.positive_integer", i18n.Args{"field": "last_event_id"}))
+	}
+
+	return id, nil
+}
+
+// writeEvent writes a single activity as a Server-Sent Event.
+func writeEvent(w http.ResponseWriter, activity *model.Activity) error {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", activity.Id, activity.Type, data)
+	return err
+}
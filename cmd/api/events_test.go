@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGroupEventsRequiresMembership(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+	router := app.Router()
+
+	// anonymous streams are refused before reading the group
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/groups/3/events", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+
+	// an EventSource passes its token in the query string
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/groups/3/events?access_token="+testToken(t, "2", false), nil)
+	r.Header.Set("Accept", "text/event-stream")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("non-member: status %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/tracing"
+)
+
+// representations of list endpoints, negotiated through the Accept header
+const (
+	contentTypeJSON   = "application/json"
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// groupRepresentations lists the representations of GET /v1/groups in order of preference.
+var groupRepresentations = []string{contentTypeJSON, contentTypeCSV, contentTypeNDJSON, "application/ndjson"}
+
+// groupSingleRepresentations lists one media type per representation of GET
+// /v1/groups, leaving out the aliases, for the 406 Not Acceptable response.
+var groupSingleRepresentations = groupRepresentations[:3]
+
+// groupEncoder writes the groups of an export in one representation.
+type groupEncoder interface {
+	Begin() error              // writes what precedes the first group
+	Encode(*model.Group) error // writes one group
+	End() error                // writes what follows the last group and flushes
+}
+
+// streamGroups writes every group matching filters to w with enc as the rows
+// are read from the database, so the export is never held in memory. Errors
+// before the first group are reported with a 500 response; once the response
+// has started, an error can only end the body early, and is logged.
+func (app *application) streamGroups(w http.ResponseWriter, r *http.Request, filters *model.GroupQuery, contentType string, enc groupEncoder) {
+	ctx, span := tracing.Tracer().Start(r.Context(), "stream response")
+	defer span.End()
+
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		if contentType == contentTypeCSV {
+			w.Header().Set("Content-Disposition", `attachment; filename="groups.csv"`)
+		}
+		w.WriteHeader(http.StatusOK)
+		return enc.Begin()
+	}
+
+	err := app.Models.Groups.Stream(ctx, filters, func(group *model.Group) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return enc.Encode(group)
+	})
+
+	if err == nil && !started {
+		err = start()
+	}
+
+	if err == nil {
+		err = enc.End()
+	}
+
+	if err != nil {
+		if !started {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+		internal.NewLogger().Log.Error().Err(err).Str("content_type", contentType).Msg("failed to stream groups")
+	}
+}
+
+// csvGroupEncoder writes groups as CSV rows below a header row. Text a user
+// entered is written through csvCell, so that spreadsheets opening the export
+// do not run it as a formula.
+type csvGroupEncoder struct {
+	w *csv.Writer
+}
+
+// newCSVGroupEncoder returns a csvGroupEncoder writing to w.
+func newCSVGroupEncoder(w http.ResponseWriter) *csvGroupEncoder {
+	return &csvGroupEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvGroupEncoder) Begin() error {
+	return e.w.Write([]string{"id", "name", "currency", "description", "created_by", "created_at", "version"})
+}
+
+func (e *csvGroupEncoder) Encode(group *model.Group) error {
+	return e.w.Write([]string{
+		strconv.Itoa(group.Id),
+		csvCell(group.Name),
+		csvCell(group.Currency),
+		csvCell(group.Description),
+		strconv.Itoa(group.CreatedBy),
+		group.CreatedAt,
+		strconv.Itoa(group.Version),
+	})
+}
+
+func (e *csvGroupEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvCell neutralises text starting with a character spreadsheets read as the
+// start of a formula (=, +, - or @, and the tab and carriage return some of
+// them skip first) by prefixing it with a single quote, which makes them
+// display it as text.
+func csvCell(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// ndjsonGroupEncoder writes groups as newline delimited JSON, one object per line.
+type ndjsonGroupEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+// newNDJSONGroupEncoder returns an ndjsonGroupEncoder writing to w.
+func newNDJSONGroupEncoder(w http.ResponseWriter) *ndjsonGroupEncoder {
+	buf := bufio.NewWriter(w)
+	return &ndjsonGroupEncoder{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (e *ndjsonGroupEncoder) Begin() error {
+	return nil
+}
+
+func (e *ndjsonGroupEncoder) Encode(group *model.Group) error {
+	return e.enc.Encode(group)
+}
+
+func (e *ndjsonGroupEncoder) End() error {
+	return e.buf.Flush()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+func TestCSVGroupEncoderNeutralisesFormulas(t *testing.T) {
+	w := httptest.NewRecorder()
+	enc := newCSVGroupEncoder(w)
+
+	groups := []*model.Group{
+		{Id: 1, Name: "=HYPERLINK(\"http://evil.example\",\"Click\")", Currency: "Euro", Description: "+1", CreatedBy: 1, Version: 1},
+		{Id: 2, Name: "-2+3", Currency: "Euro", Description: "@SUM(A1:A2)", CreatedBy: 1, Version: 1},
+		{Id: 3, Name: "\t=1", Currency: "Euro", Description: "Trip = fun", CreatedBy: 1, Version: 1},
+	}
+
+	if err := enc.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range groups {
+		if err := enc.Encode(group); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]string{
+		{"'=HYPERLINK(\"http://evil.example\",\"Click\")", "'+1"},
+		{"'-2+3", "'@SUM(A1:A2)"},
+		{"'\t=1", "Trip = fun"},
+	}
+
+	if len(records) != len(want)+1 {
+		t.Fatalf("got %d records, want a header and %d groups", len(records), len(want))
+	}
+	for i, cells := range want {
+		record := records[i+1]
+		if record[1] != cells[0] || record[3] != cells[1] {
+			t.Errorf("record %d has name %q and description %q, want %q and %q", i+1, record[1], record[3], cells[0], cells[1])
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// graphQLRequest is a GraphQL request, sent as the JSON body of a POST request
+// or as the query parameters of a GET request, with variables encoded as JSON.
+type graphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphQLQuery documents the query parameters of GET /v1/graphql.
+type graphQLQuery struct {
+	Query         string `json:"query" validate:"required"`
+	OperationName string `json:"operationName"`
+	Variables     string `json:"variables"`
+}
+
+// graphQLResponse is the body of every GraphQL response.
+type graphQLResponse struct {
+	Data   any                        `json:"data"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// GraphQL error codes, reported in the extensions of each error along with the
+// HTTP status a REST request failing the same way responds with.
+const (
+	graphQLInvalidQuery    = "invalid_query"
+	graphQLInvalidRequest  = "invalid_request"
+	graphQLNotFound        = "not_found"
+	graphQLEditConflict    = "edit_conflict"
+	graphQLUnauthorized    = "unauthorized"
+	graphQLForbidden       = "forbidden"
+	graphQLQueryTooComplex = "query_too_complex"
+	graphQLQueryTooDeep    = "query_too_deep"
+	graphQLInternal        = "internal"
+)
+
+// listSizes holds the number of items assumed for list fields without a first
+// argument when the cost of a query is computed.
+var listSizes = map[string]int{
+	"groups":     10,
+	"activities": 20,
+	"members":    10,
+}
+
+// graphQLHandler returns the handler of /v1/graphql executing queries against
+// schema. Mutations are only accepted in POST requests.
+//
+// Queries are parsed, validated and checked against the complexity and depth
+// limits before they run. Errors are reported in the errors of a 200 response,
+// each with the code and status of the matching REST error in its extensions.
+func (app *application) graphQLHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.FromContext(r.Context())
+		val := internal.NewValidator(r)
+
+		req := graphQLRequest{}
+		switch r.Method {
+		case http.MethodPost:
+			if err := internal.ReadJSON(w, r, &req); err != nil {
+				internal.BadRequestError(w, r, err.Error())
+				return
+			}
+		default:
+			req.Query = internal.ReadQueryString(r, "query", "")
+			req.OperationName = internal.ReadQueryString(r, "operationName", "")
+			if variables := internal.ReadQueryString(r, "variables", ""); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					val.AddCode("variables", "query.json", i18n.Args{"field": "variables"})
+				}
+			}
+		}
+
+		if !val.Struct(&req) {
+			internal.BadRequestError(w, r, val.Errors)
+			return
+		}
+
+		doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+		if err != nil {
+			app.writeGraphQL(w, r, nil, []gqlerrors.FormattedError{gqlerrors.FormatError(err)})
+			return
+		}
+
+		if result := graphql.ValidateDocument(&schema, doc, nil); !result.IsValid {
+			app.writeGraphQL(w, r, nil, result.Errors)
+			return
+		}
+
+		if op := findOperation(doc, req.OperationName); op != nil {
+			if op.Operation == ast.OperationTypeMutation && r.Method != http.MethodPost {
+				internal.MethodNotAllowed(w, r)
+				return
+			}
+
+			cost, depth := queryCost(op.SelectionSet, fragments(doc), req.Variables)
+			if limit := app.Config.GraphQL.MaxDepth; limit > 0 && depth > limit {
+				message := i18n.T(lang, "error.query_too_deep", i18n.Args{"depth": depth, "limit": limit})
+				app.writeGraphQL(w, r, nil, []gqlerrors.FormattedError{graphQLError(message, graphQLQueryTooDeep, http.StatusBadRequest)})
+				return
+			}
+			if limit := app.Config.GraphQL.MaxComplexity; limit > 0 && cost > limit {
+				message := i18n.T(lang, "error.query_too_complex", i18n.Args{"cost": cost, "limit": limit})
+				app.writeGraphQL(w, r, nil, []gqlerrors.FormattedError{graphQLError(message, graphQLQueryTooComplex, http.StatusBadRequest)})
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), graphQLLoadersKey{}, app.newGraphQLLoaders())
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:        schema,
+			AST:           doc,
+			OperationName: req.OperationName,
+			Args:          req.Variables,
+			Context:       ctx,
+		})
+
+		app.writeGraphQL(w, r, result.Data, result.Errors)
+	}
+}
+
+// writeGraphQL writes a GraphQL response with data and errs, replacing the
+// message and extensions of errors returned by resolvers with those of the
+// matching REST error.
+func (app *application) writeGraphQL(w http.ResponseWriter, r *http.Request, data any, errs []gqlerrors.FormattedError) {
+	lang := i18n.FromContext(r.Context())
+
+	for i, formatted := range errs {
+		if formatted.Extensions != nil {
+			continue
+		}
+
+		var fields validationErrors
+		err := originalError(formatted)
+		switch {
+		case err == nil:
+			// parse and validation errors describe the query itself
+			errs[i].Extensions = graphQLExtensions(graphQLInvalidQuery, http.StatusBadRequest)
+		case errors.Is(err, internal.ErrNotFound):
+			errs[i].Message = i18n.T(lang, "error.not_found", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLNotFound, http.StatusNotFound)
+		case errors.Is(err, internal.ErrEditConflict):
+			errs[i].Message = i18n.T(lang, "error.edit_conflict", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLEditConflict, http.StatusConflict)
+		case errors.Is(err, internal.ErrUnauthorized):
+			errs[i].Message = i18n.T(lang, "error.unauthorized", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLUnauthorized, http.StatusUnauthorized)
+		case errors.Is(err, internal.ErrForbidden):
+			errs[i].Message = i18n.T(lang, "error.forbidden", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLForbidden, http.StatusForbidden)
+		case errors.As(err, &fields):
+			errs[i].Message = i18n.T(lang, "error.invalid_request", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLInvalidRequest, http.StatusBadRequest)
+			errs[i].Extensions["fields"] = map[string]string(fields)
+		default:
+			errs[i].Message = i18n.T(lang, "error.internal", nil)
+			errs[i].Extensions = graphQLExtensions(graphQLInternal, http.StatusInternalServerError)
+			internal.NewLogger().Log.Error().Err(err).Msg("internal server error")
+		}
+	}
+
+	internal.WriteJSON(w, http.StatusOK, graphQLResponse{Data: data, Errors: errs})
+}
+
+// originalError returns the error a resolver returned that caused formatted,
+// or nil when formatted was raised while parsing or validating the query.
+func originalError(formatted gqlerrors.FormattedError) error {
+	var err error = formatted
+	for {
+		switch e := err.(type) {
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return err
+		}
+	}
+}
+
+// graphQLError returns an error raised before the query runs.
+func graphQLError(message string, code string, status int) gqlerrors.FormattedError {
+	err := gqlerrors.NewFormattedError(message)
+	err.Extensions = graphQLExtensions(code, status)
+	return err
+}
+
+// graphQLExtensions returns the extensions of an error with code and status.
+func graphQLExtensions(code string, status int) map[string]any {
+	return map[string]any{"code": code, "status": status}
+}
+
+// findOperation returns the operation of doc named name, or its only operation
+// when name is empty. It returns nil when there is no such operation, which
+// Execute then reports.
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "" && found != nil:
+			return nil
+		case name == "":
+			found = op
+		case op.Name != nil && op.Name.Value == name:
+			return op
+		}
+	}
+	return found
+}
+
+// fragments returns the fragment definitions of doc by name.
+func fragments(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return fragments
+}
+
+// queryCost returns the cost and depth of the fields selected by set. Each
+// field costs one, and the fields selected under a list field cost once per
+// item it may return: its first argument, or its size in listSizes.
+// Introspection fields are free. The document must have been validated, so
+// fragments do not spread into themselves.
+func queryCost(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, variables map[string]any) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	cost, depth := 0, 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+
+			childCost, childDepth := queryCost(selection.SelectionSet, fragments, variables)
+			cost += 1 + listSize(selection, variables)*childCost
+			depth = max(depth, 1+childDepth)
+		case *ast.InlineFragment:
+			childCost, childDepth := queryCost(selection.SelectionSet, fragments, variables)
+			cost += childCost
+			depth = max(depth, childDepth)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[selection.Name.Value]; ok {
+				childCost, childDepth := queryCost(fragment.SelectionSet, fragments, variables)
+				cost += childCost
+				depth = max(depth, childDepth)
+			}
+		}
+	}
+
+	return cost, depth
+}
+
+// listSize returns the number of items field may return: the value of its
+// first argument, its size in listSizes, or one when it is not a list.
+func listSize(field *ast.Field, variables map[string]any) int {
+	size, ok := listSizes[field.Name.Value]
+	if !ok {
+		size = 1
+	}
+
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "first" {
+			continue
+		}
+
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if first, err := strconv.Atoi(value.Value); err == nil {
+				size = first
+			}
+		case *ast.Variable:
+			switch first := variables[value.Name.Value].(type) {
+			case float64:
+				size = int(first)
+			case int:
+				size = first
+			}
+		}
+	}
+
+	return max(size, 0)
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/dataloader"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/validation"
+	"github.com/graphql-go/graphql"
+)
+
+// graphQLLoaders holds the data loaders of a GraphQL request.
+type graphQLLoaders struct {
+	members    *dataloader.Loader[int, []*model.Member]                     // members of a group, by group id
+	activities *dataloader.Loader[model.ActivityQuery, *model.ActivityPage] // feed pages, by query
+}
+
+// graphQLLoadersKey is the context key of the data loaders of a GraphQL request.
+type graphQLLoadersKey struct{}
+
+// newGraphQLLoaders returns fresh data loaders for a GraphQL request.
+func (app *application) newGraphQLLoaders() *graphQLLoaders {
+	return &graphQLLoaders{
+		members:    dataloader.New(app.Models.Members.GetByGroups),
+		activities: dataloader.New(app.Models.Activities.GetFeeds),
+	}
+}
+
+// loaders returns the data loaders of the GraphQL request of ctx.
+func loaders(ctx context.Context) *graphQLLoaders {
+	return ctx.Value(graphQLLoadersKey{}).(*graphQLLoaders)
+}
+
+// validationErrors is returned by resolvers for invalid arguments; its fields
+// are reported like the body of a REST 400 response.
+type validationErrors map[string]string
+
+// Error returns the invalid fields.
+func (e validationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field, message := range e {
+		fields = append(fields, field+": "+message)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// graphQLSchema returns the GraphQL schema of the API, whose resolvers use the
+// same repository models, validation and errors as the REST handlers.
+func (app *application) graphQLSchema() (graphql.Schema, error) {
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"endCursor":   &graphql.Field{Type: graphql.String, Description: "Cursor to pass as after to read the next page"},
+		},
+	})
+
+	memberType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Member",
+		Fields: graphql.Fields{
+			"userId":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: field(func(m *model.Member) any { return m.UserId })},
+			"role":     &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(m *model.Member) any { return m.Role })},
+			"joinedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(m *model.Member) any { return m.JoinedAt })},
+		},
+	})
+
+	fieldChangeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FieldChange",
+		Fields: graphql.Fields{
+			"field": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(c model.FieldChange) any { return c.Field })},
+			"from":  &graphql.Field{Type: graphql.String, Resolve: field(func(c model.FieldChange) any { return c.From })},
+			"to":    &graphql.Field{Type: graphql.String, Resolve: field(func(c model.FieldChange) any { return c.To })},
+		},
+	})
+
+	activityType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Activity",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: field(func(a *model.Activity) any { return a.Id })},
+			"actorId":   &graphql.Field{Type: graphql.Int, Resolve: field(func(a *model.Activity) any { return a.ActorId })},
+			"type":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(a *model.Activity) any { return a.Type })},
+			"message":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(a *model.Activity) any { return a.Message })},
+			"changes":   &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(fieldChangeType)), Resolve: field(func(a *model.Activity) any { return a.Changes })},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(a *model.Activity) any { return a.CreatedAt })},
+		},
+	})
+
+	activityConnectionType := connectionType("Activity", activityType, pageInfoType)
+
+	groupType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Group",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: field(func(g *model.Group) any { return g.Id })},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(g *model.Group) any { return g.Name })},
+			"currency":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(g *model.Group) any { return g.Currency })},
+			"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(g *model.Group) any { return g.Description })},
+			"createdBy":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: field(func(g *model.Group) any { return g.CreatedBy })},
+			"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: field(func(g *model.Group) any { return g.CreatedAt })},
+			"version":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: field(func(g *model.Group) any { return g.Version })},
+			"members": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(memberType))),
+				Description: "Members of the group, owners first. Only readable by members and administrators",
+				Resolve:     app.resolveGroupMembers,
+			},
+			"activities": &graphql.Field{
+				Type:        graphql.NewNonNull(activityConnectionType),
+				Description: "Activity feed of the group, newest first. Only readable by members and administrators",
+				Args: graphql.FieldConfigArgument{
+					"first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20},
+					"after": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: app.resolveGroupActivities,
+			},
+		},
+	})
+
+	groupConnectionType := connectionType("Group", groupType, pageInfoType)
+
+	groupInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "GroupInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"currency":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String, DefaultValue: ""},
+			"createdBy":   &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 0},
+		},
+	})
+
+	groupPatchType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "GroupPatch",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"currency":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"group": &graphql.Field{
+				Type: graphql.NewNonNull(groupType),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: app.resolveGroup,
+			},
+			"groups": &graphql.Field{
+				Type:        graphql.NewNonNull(groupConnectionType),
+				Description: "Groups matching the filters, like GET /v1/groups",
+				Args: graphql.FieldConfigArgument{
+					"first":       &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+					"after":       &graphql.ArgumentConfig{Type: graphql.String},
+					"name":        &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+					"currency":    &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"description": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+					"filter":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+					"sort":        &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				},
+				Resolve: app.resolveGroups,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createGroup": &graphql.Field{
+				Type: graphql.NewNonNull(groupType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(groupInputType)},
+				},
+				Resolve: app.resolveCreateGroup,
+			},
+			"updateGroup": &graphql.Field{
+				Type:        graphql.NewNonNull(groupType),
+				Description: "Replaces the name, currency and description of a group",
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(groupInputType)},
+				},
+				Resolve: app.resolveUpdateGroup,
+			},
+			"patchGroup": &graphql.Field{
+				Type:        graphql.NewNonNull(groupType),
+				Description: "Changes the fields of a group given in the input",
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(groupPatchType)},
+				},
+				Resolve: app.resolvePatchGroup,
+			},
+			"deleteGroup": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Deletes a group, returning true",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: app.resolveDeleteGroup,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// connectionType returns the Relay style connection type listing nodes of
+// nodeType, named after name, e.g. GroupConnection with GroupEdge edges.
+func connectionType(name string, nodeType *graphql.Object, pageInfoType *graphql.Object) *graphql.Object {
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(nodeType)},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+			"nodes":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(nodeType)))},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+			"totalCount": &graphql.Field{Type: graphql.Int, Description: "Number of nodes across all pages, when known"},
+		},
+	})
+}
+
+// newConnection returns the connection of nodes, whose cursors are returned by
+// cursor. hasNext tells whether more nodes follow the last one.
+func newConnection[T any](nodes []T, cursor func(i int, node T) string, hasNext bool, totalCount *int) map[string]any {
+	edges := make([]map[string]any, len(nodes))
+	values := make([]any, len(nodes))
+	var endCursor any
+
+	for i, node := range nodes {
+		c := cursor(i, node)
+		edges[i] = map[string]any{"cursor": c, "node": node}
+		values[i] = node
+		endCursor = c
+	}
+
+	return map[string]any{
+		"edges":      edges,
+		"nodes":      values,
+		"pageInfo":   map[string]any{"hasNextPage": hasNext, "endCursor": endCursor},
+		"totalCount": totalCount,
+	}
+}
+
+// field returns a resolver reading a field of a source of type T.
+func field[T any](get func(T) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(T)
+		if !ok {
+			return nil, nil
+		}
+		return get(source), nil
+	}
+}
+
+// encodeCursor returns the opaque cursor of a position in a list of kind.
+func encodeCursor(kind string, position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + ":" + strconv.FormatInt(position, 10)))
+}
+
+// decodeCursor returns the position of a cursor of kind, or false when the
+// cursor is malformed or of another kind.
+func decodeCursor(kind string, cursor string) (int64, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+
+	value, ok := strings.CutPrefix(string(data), kind+":")
+	if !ok {
+		return 0, false
+	}
+
+	position, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || position < 0 {
+		return 0, false
+	}
+
+	return position, true
+}
+
+// argString returns the string argument name of p, or "" when it is null.
+func argString(p graphql.ResolveParams, name string) string {
+	value, _ := p.Args[name].(string)
+	return value
+}
+
+// argStrings returns the list of strings argument name of p, empty when it is null.
+func argStrings(p graphql.ResolveParams, name string) []string {
+	values, _ := p.Args[name].([]any)
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, value.(string))
+	}
+	return strs
+}
+
+// newGraphQLValidator returns a validator reporting messages in the language of
+// the GraphQL request of ctx.
+func newGraphQLValidator(ctx context.Context) *validation.Validator {
+	return validation.NewLocalized(i18n.FromContext(ctx))
+}
+
+// readAfter reads the after cursor argument of kind into val, returning the
+// position it points to, or zero when there is none.
+func readAfter(p graphql.ResolveParams, val *validation.Validator, kind string) int64 {
+	after := argString(p, "after")
+	if after == "" {
+		return 0
+	}
+
+	position, ok := decodeCursor(kind, after)
+	if !ok {
+		val.AddCode("after", "query.cursor", i18n.Args{"field": "after"})
+	}
+	return position
+}
+
+// resolveGroup resolves the group query.
+func (app *application) resolveGroup(p graphql.ResolveParams) (any, error) {
+	return app.Models.Groups.Get(p.Context, p.Args["id"].(int))
+}
+
+// resolveGroups resolves the groups query with the filters, validation and
+// sort order of GET /v1/groups. Its cursors hold the offset of each group.
+func (app *application) resolveGroups(p graphql.ResolveParams) (any, error) {
+	val := newGraphQLValidator(p.Context)
+
+	filters := model.GroupQuery{
+		Name:        argString(p, "name"),
+		Currency:    argStrings(p, "currency"),
+		Description: argString(p, "description"),
+		Filter:      argString(p, "filter"),
+		Sort:        argStrings(p, "sort"),
+		Page:        1,
+		PageSize:    p.Args["first"].(int),
+	}
+
+	// a cursor is the offset of a group; the page starts after it
+	if argString(p, "after") != "" {
+		filters.Offset = int(readAfter(p, val, "group")) + 1
+	}
+
+	if errs := filters.ValidateGroupQuery(val); errs != nil {
+		if _, ok := errs["page_size"]; ok {
+			errs["first"] = errs["page_size"]
+			delete(errs, "page_size")
+		}
+		return nil, validationErrors(errs)
+	}
+
+	groups, meta, err := app.Models.Groups.GetAll(p.Context, &filters)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := func(i int, _ *model.Group) string {
+		return encodeCursor("group", int64(filters.Offset+i))
+	}
+	hasNext := filters.Offset+len(groups) < meta.Total
+
+	return newConnection(groups, cursor, hasNext, &meta.Total), nil
+}
+
+// resolveGroupMembers resolves the members of a group through the members data
+// loader, so the members of every group of a list are read in one query.
+func (app *application) resolveGroupMembers(p graphql.ResolveParams) (any, error) {
+	group := p.Source.(*model.Group)
+	thunk := readableGroup(p.Context, group.Id)
+
+	return func() (any, error) {
+		members, err := thunk()
+		if members == nil {
+			members = []*model.Member{}
+		}
+		return members, err
+	}, nil
+}
+
+// readableGroup returns a thunk returning the members of the group identified
+// by groupId, read through the members data loader, or an error when the user
+// of ctx may not read the group. Like the requireGroupMember routes, only
+// members and administrators may read it.
+func readableGroup(ctx context.Context, groupId int) func() ([]*model.Member, error) {
+	thunk := loaders(ctx).members.Load(ctx, groupId)
+
+	return func() ([]*model.Member, error) {
+		members, err := thunk()
+		if err != nil || internal.ContextIsAdmin(ctx) {
+			return members, err
+		}
+
+		userId, ok := internal.ContextGetActor(ctx)
+		if !ok {
+			return nil, internal.ErrUnauthorized
+		}
+		for _, member := range members {
+			if member.UserId == userId {
+				return members, nil
+			}
+		}
+		return nil, internal.ErrForbidden
+	}
+}
+
+// resolveGroupActivities resolves the activity feed of a group with the cursor
+// pagination of GET /v1/groups/:id/activity through the activities data loader,
+// so the feeds of every group of a list are read in one query. Its cursors hold
+// activity ids.
+func (app *application) resolveGroupActivities(p graphql.ResolveParams) (any, error) {
+	group := p.Source.(*model.Group)
+	val := newGraphQLValidator(p.Context)
+
+	filters := model.ActivityQuery{
+		GroupId: group.Id,
+		Cursor:  readAfter(p, val, "activity"),
+		Limit:   p.Args["first"].(int),
+	}
+
+	if errs := filters.ValidateActivityQuery(val); errs != nil {
+		if _, ok := errs["limit"]; ok {
+			errs["first"] = errs["limit"]
+			delete(errs, "limit")
+		}
+		return nil, validationErrors(errs)
+	}
+
+	readable := readableGroup(p.Context, group.Id)
+	feed := loaders(p.Context).activities.Load(p.Context, filters)
+
+	return func() (any, error) {
+		if _, err := readable(); err != nil {
+			return nil, err
+		}
+
+		page, err := feed()
+		if err != nil {
+			return nil, err
+		}
+
+		cursor := func(_ int, activity *model.Activity) string {
+			return encodeCursor("activity", activity.Id)
+		}
+
+		return newConnection(page.Activities, cursor, page.Metadata.NextCursor != nil, nil), nil
+	}, nil
+}
+
+// groupFromInput returns the group described by the GroupInput argument.
+func groupFromInput(p graphql.ResolveParams) model.Group {
+	input := p.Args["input"].(map[string]any)
+
+	group := model.Group{
+		Name:     input["name"].(string),
+		Currency: input["currency"].(string),
+	}
+	group.Description, _ = input["description"].(string)
+	group.CreatedBy, _ = input["createdBy"].(int)
+
+	return group
+}
+
+// resolveCreateGroup resolves the createGroup mutation like POST /v1/groups.
+func (app *application) resolveCreateGroup(p graphql.ResolveParams) (any, error) {
+	group := groupFromInput(p)
+
+	if errs := group.Validate(newGraphQLValidator(p.Context)); errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	if err := app.insertGroup(p.Context, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// resolveUpdateGroup resolves the updateGroup mutation like PUT /v1/groups/:id.
+func (app *application) resolveUpdateGroup(p graphql.ResolveParams) (any, error) {
+	group := groupFromInput(p)
+	group.Id = p.Args["id"].(int)
+
+	if errs := group.Validate(newGraphQLValidator(p.Context)); errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	if err := app.replaceGroup(p.Context, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// resolvePatchGroup resolves the patchGroup mutation like PATCH /v1/groups/:id.
+func (app *application) resolvePatchGroup(p graphql.ResolveParams) (any, error) {
+	input := model.GroupUpdate{}
+	for name, value := range p.Args["input"].(map[string]any) {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "name":
+			input.Name = &text
+		case "currency":
+			input.Currency = &text
+		case "description":
+			input.Description = &text
+		}
+	}
+
+	group, errs, err := app.patchGroup(p.Context, p.Args["id"].(int), input, newGraphQLValidator(p.Context))
+	if err != nil {
+		return nil, err
+	}
+	if errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	return group, nil
+}
+
+// resolveDeleteGroup resolves the deleteGroup mutation like DELETE /v1/groups/:id.
+func (app *application) resolveDeleteGroup(p graphql.ResolveParams) (any, error) {
+	if err := app.Models.Groups.DeleteGroup(p.Context, p.Args["id"].(int)); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// graphQLResult is the body of a GraphQL response as read by the tests.
+type graphQLResult struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newGraphQLTest returns an application backed by a mock database, failing the
+// test when expectations are left unmet.
+func newGraphQLTest(t *testing.T) (*application, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return app, mock
+}
+
+// postGraphQL posts query to /v1/graphql with the bearer token, if any, and
+// returns the decoded response.
+func postGraphQL(t *testing.T, app *application, token string, query string) graphQLResult {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	r := httptest.NewRequest(http.MethodPost, "/v1/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	app.Router().ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	result := graphQLResult{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestGraphQLGroupMembersRequireMembership(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(t *testing.T) string
+		code  string
+	}{
+		{"anonymous", func(t *testing.T) string { return "" }, graphQLUnauthorized},
+		{"non-member", func(t *testing.T) string { return testToken(t, "2", false) }, graphQLForbidden},
+		{"member", func(t *testing.T) string { return testToken(t, "1", false) }, ""},
+		{"admin", func(t *testing.T) string { return testToken(t, "2", true) }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, mock := newGraphQLTest(t)
+
+			mock.ExpectQuery(`SELECT id, name, currency`).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+			mock.ExpectQuery(`FROM group_members`).
+				WillReturnRows(sqlmock.NewRows([]string{"group_id", "user_id", "role", "joined_at"}).AddRow(3, 1, "owner", "2026-01-01T00:00:00Z"))
+
+			result := postGraphQL(t, app, tt.token(t), `{ group(id: 3) { members { userId } } }`)
+
+			if tt.code == "" {
+				if len(result.Errors) > 0 || result.Data["group"] == nil {
+					t.Fatalf("errors %+v, want the members of the group", result.Errors)
+				}
+				return
+			}
+
+			if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != tt.code {
+				t.Fatalf("errors %+v, want code %s", result.Errors, tt.code)
+			}
+		})
+	}
+}
+
+// errorCode returns the code in the extensions of the only error of result.
+func errorCode(t *testing.T, result graphQLResult) string {
+	t.Helper()
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("errors %+v, want one error", result.Errors)
+	}
+	code, _ := result.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		complexity int
+		depth      int
+		query      string
+		code       string
+	}{
+		{"too complex", 20, 0, `{ groups(first: 10) { nodes { id name } } }`, graphQLQueryTooComplex},
+		{"first lowers the cost", 30, 0, `{ groups(first: 5) { nodes { id name } } }`, ""},
+		{"too deep", 0, 2, `{ groups { nodes { id } } }`, graphQLQueryTooDeep},
+		{"fragments count", 0, 3, `{ groups { nodes { ...g } } } fragment g on Group { members { userId } }`, graphQLQueryTooDeep},
+		{"introspection is free", 1, 1, `{ __schema { types { name } } }`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, mock := newGraphQLTest(t)
+			app.Config.GraphQL.MaxComplexity = tt.complexity
+			app.Config.GraphQL.MaxDepth = tt.depth
+
+			if tt.code != "" {
+				result := postGraphQL(t, app, "", tt.query)
+				if code := errorCode(t, result); code != tt.code {
+					t.Errorf("code %q, want %q", code, tt.code)
+				}
+				if status := result.Errors[0].Extensions["status"]; status != float64(http.StatusBadRequest) {
+					t.Errorf("status %v, want %d", status, http.StatusBadRequest)
+				}
+				return
+			}
+
+			if strings.Contains(tt.query, "groups") {
+				mock.ExpectQuery(`FROM groups`).WillReturnRows(sqlmock.NewRows(append([]string{"count"}, groupColumns...)))
+			}
+			if result := postGraphQL(t, app, "", tt.query); len(result.Errors) > 0 {
+				t.Errorf("errors %+v, want none", result.Errors)
+			}
+		})
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		expect func(mock sqlmock.Sqlmock)
+		code   string
+		status int
+	}{
+		{"syntax", `{ groups {`, nil, graphQLInvalidQuery, http.StatusBadRequest},
+		{"unknown field", `{ groups { nodes { secret } } }`, nil, graphQLInvalidQuery, http.StatusBadRequest},
+		{"not found", `{ group(id: 3) { id } }`, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM groups WHERE id`).WithArgs(3).WillReturnRows(sqlmock.NewRows(groupColumns))
+		}, graphQLNotFound, http.StatusNotFound},
+		{"invalid argument", `{ groups(first: 0) { nodes { id } } }`, nil, graphQLInvalidRequest, http.StatusBadRequest},
+		{"edit conflict", `mutation { patchGroup(id: 3, input: {name: "Lisbon"}) { id } }`, func(mock sqlmock.Sqlmock) {
+			// the group changes between the read and the locked read of the update
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FROM groups WHERE id = \$1;`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+			mock.ExpectQuery(`FOR UPDATE`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 2))
+			mock.ExpectRollback()
+		}, graphQLEditConflict, http.StatusConflict},
+		{"internal", `{ group(id: 3) { id } }`, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM groups WHERE id`).WithArgs(3).WillReturnError(errors.New("connection reset"))
+		}, graphQLInternal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, mock := newGraphQLTest(t)
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			result := postGraphQL(t, app, testToken(t, "1", false), tt.query)
+			if code := errorCode(t, result); code != tt.code {
+				t.Errorf("code %q, want %q", code, tt.code)
+			}
+			if status := result.Errors[0].Extensions["status"]; status != float64(tt.status) {
+				t.Errorf("status %v, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestGraphQLCreateGroup(t *testing.T) {
+	app, mock := newGraphQLTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO groups`).
+		WithArgs("Trip", "Euro", "Lisbon", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "version"}).AddRow(3, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "create", "group", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectQuery(`INSERT INTO group_members`).
+		WithArgs(3, 1, "owner").
+		WillReturnRows(sqlmock.NewRows([]string{"joined_at"}).AddRow("2026-01-01T00:00:00Z"))
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(1, "create", "member", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectActivity(mock)
+	mock.ExpectCommit()
+
+	result := postGraphQL(t, app, testToken(t, "1", false),
+		`mutation { createGroup(input: {name: "Trip", currency: "Euro", description: "Lisbon", createdBy: 1}) { id version } }`)
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors %+v, want none", result.Errors)
+	}
+
+	group, _ := result.Data["createGroup"].(map[string]any)
+	if group["id"] != float64(3) || group["version"] != float64(1) {
+		t.Errorf("createGroup = %v, want group 3 at version 1", group)
+	}
+
+	// the mutation is counted like a group created over REST
+	w := httptest.NewRecorder()
+	app.Metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "groups_created_total 1") {
+		t.Error("metrics do not count the created group")
+	}
+}
+
+func TestGraphQLCreateGroupInvalid(t *testing.T) {
+	app, _ := newGraphQLTest(t)
+
+	result := postGraphQL(t, app, testToken(t, "1", false),
+		`mutation { createGroup(input: {name: "Trip", currency: "Yen"}) { id } }`)
+	if code := errorCode(t, result); code != graphQLInvalidRequest {
+		t.Fatalf("code %q, want %q", code, graphQLInvalidRequest)
+	}
+
+	fields, _ := result.Errors[0].Extensions["fields"].(map[string]any)
+	if _, ok := fields["currency"]; !ok {
+		t.Errorf("fields %v, want an error for currency", fields)
+	}
+}
+
+func TestGraphQLBatchesGroupFields(t *testing.T) {
+	app, mock := newGraphQLTest(t)
+
+	mock.ExpectQuery(`FROM groups`).
+		WillReturnRows(sqlmock.NewRows(append([]string{"count"}, groupColumns...)).
+			AddRow(2, 3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1).
+			AddRow(2, 4, "Flat", "Pound", "", 1, "2026-01-01T00:00:00Z", 1))
+
+	// a single query reads the members, and another the feeds, of both groups
+	mock.ExpectQuery(`FROM group_members`).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id", "user_id", "role", "joined_at"}).
+			AddRow(3, 1, "owner", "2026-01-01T00:00:00Z").
+			AddRow(4, 1, "owner", "2026-01-01T00:00:00Z"))
+	mock.ExpectQuery(`FROM unnest`).
+		WillReturnRows(sqlmock.NewRows([]string{"n", "id", "group_id", "actor_id", "type", "message", "changes", "created_at"}).
+			AddRow(1, 12, 3, 1, "group_renamed", "renamed", []byte("[]"), "2026-01-01T00:00:00Z").
+			AddRow(1, 11, 3, 1, "group_created", "created", []byte("[]"), "2026-01-01T00:00:00Z").
+			AddRow(2, 21, 4, 1, "group_created", "created", []byte("[]"), "2026-01-01T00:00:00Z"))
+
+	result := postGraphQL(t, app, testToken(t, "1", false),
+		`{ groups { nodes { id activities(first: 1) { nodes { id } pageInfo { hasNextPage } } } } }`)
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors %+v, want none", result.Errors)
+	}
+
+	groups := result.Data["groups"].(map[string]any)["nodes"].([]any)
+	want := []struct {
+		activity string
+		hasNext  bool
+	}{{"12", true}, {"21", false}}
+
+	for i, group := range groups {
+		activities := group.(map[string]any)["activities"].(map[string]any)
+		nodes := activities["nodes"].([]any)
+		hasNext := activities["pageInfo"].(map[string]any)["hasNextPage"]
+
+		if len(nodes) != 1 || nodes[0].(map[string]any)["id"] != want[i].activity || hasNext != want[i].hasNext {
+			t.Errorf("group %d activities = %v, want activity %v with hasNextPage %v", i, activities, want[i].activity, want[i].hasNext)
+		}
+	}
+}
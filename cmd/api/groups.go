@@ -1,16 +1,20 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/Abdul4code/FairShare/internal"
 	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/Abdul4code/FairShare/internal/tracing"
 	"github.com/Abdul4code/FairShare/internal/validation"
 )
 
 // CreateGroupHandler handles POST /v1/groups. It reads the JSON body into a
 // data.GroupInput, validates it and returns either validation errors or the created group.
+// The group and the owner membership of its creator are created atomically.
 func (app *application) CreateGroupHandler(w http.ResponseWriter, r *http.Request) {
 	groupInput := model.GroupInput{}
 
@@ -26,15 +30,13 @@ func (app *application) CreateGroupHandler(w http.ResponseWriter, r *http.Reques
 		CreatedBy:   groupInput.CreatedBy,
 	}
 
-	val := validation.New()
+	val := internal.NewValidator(r)
 	if errors := group.Validate(val); errors != nil {
 		internal.BadRequestError(w, r, errors)
 		return
 	}
 
-	err := app.Models.Groups.Insert(&group)
-
-	if err != nil {
+	if err := app.insertGroup(r.Context(), &group); err != nil {
 		internal.InternalServerError(w, r, err)
 		return
 	}
@@ -52,7 +54,7 @@ func (app *application) GetGroupHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	group, err := app.Models.Groups.Get(id)
+	group, err := app.Models.Groups.Get(r.Context(), id)
 
 	if err != nil {
 		switch {
@@ -81,7 +83,7 @@ func (app *application) UpdateGroupHandler(w http.ResponseWriter, r *http.Reques
 	groupInfo := model.GroupInput{}
 
 	if err := internal.ReadJSON(w, r, &groupInfo); err != nil {
-		internal.WriteJSON(w, 201, err.Error())
+		internal.BadRequestError(w, r, err.Error())
 		return
 	}
 
@@ -93,19 +95,21 @@ func (app *application) UpdateGroupHandler(w http.ResponseWriter, r *http.Reques
 		Id:          id,
 	}
 
-	val := validation.New()
+	val := internal.NewValidator(r)
 	if errors := group.Validate(val); errors != nil {
 		internal.BadRequestError(w, r, errors)
 		return
 	}
 
-	err = app.Models.Groups.Update(&group)
-
+	err = app.replaceGroup(r.Context(), &group)
 	if err != nil {
 		switch {
 		case errors.Is(err, internal.ErrNotFound):
 			internal.NotFoundError(w, r)
 			return
+		case errors.Is(err, internal.ErrEditConflict):
+			internal.EditConflictError(w, r)
+			return
 		default:
 			internal.InternalServerError(w, r, err)
 			return
@@ -131,43 +135,26 @@ func (app *application) PatchGroupHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	group, err := app.Models.Groups.Get(id)
-	if err != nil {
-		internal.NotFoundError(w, r)
-		return
-	}
-
-	if groupInput.Name != nil {
-		group.Name = *groupInput.Name
-	}
-
-	if groupInput.Description != nil {
-		group.Description = *groupInput.Description
-	}
-
-	if groupInput.Currency != nil {
-		group.Currency = *groupInput.Currency
-	}
-
-	val := validation.New()
-	val_errors := group.Validate(val)
-	if val_errors != nil {
-		internal.BadRequestError(w, r, val_errors)
-		return
-	}
-
-	err = app.Models.Groups.Update(group)
+	group, val_errors, err := app.patchGroup(r.Context(), id, groupInput, internal.NewValidator(r))
 	if err != nil {
 		switch {
 		case errors.Is(err, internal.ErrNotFound):
 			internal.NotFoundError(w, r)
 			return
+		case errors.Is(err, internal.ErrEditConflict):
+			internal.EditConflictError(w, r)
+			return
 		default:
 			internal.InternalServerError(w, r, err)
 			return
 		}
 	}
 
+	if val_errors != nil {
+		internal.BadRequestError(w, r, val_errors)
+		return
+	}
+
 	internal.WriteJSON(w, http.StatusOK, group)
 }
 
@@ -179,7 +166,7 @@ func (app *application) DeleteGroupHandler(w http.ResponseWriter, r *http.Reques
 		internal.NotFoundError(w, r)
 	}
 
-	err = app.Models.Groups.DeleteGroup(id)
+	err = app.Models.Groups.DeleteGroup(r.Context(), id)
 
 	if err != nil {
 		switch {
@@ -199,31 +186,156 @@ func (app *application) DeleteGroupHandler(w http.ResponseWriter, r *http.Reques
 
 // GetGroupsHandler handles GET /v1/groups. It retrieves a list of groups
 // from the database, supporting filtering, pagination, and sorting.
+//
+// The representation is chosen by the Accept header: a JSON page by default,
+// or every matching group streamed as CSV (text/csv) or NDJSON
+// (application/x-ndjson), ignoring pagination.
 func (app *application) GetGroupsHandler(w http.ResponseWriter, r *http.Request) {
-	val := validation.New()
+	val := internal.NewValidator(r)
 
+	_, span := tracing.Tracer().Start(r.Context(), "decode query")
 	filters := model.GroupQuery{
 		Page:        internal.ReadQueryInt(r, val, "page", 1),
 		PageSize:    internal.ReadQueryInt(r, val, "page_size", 10),
 		Name:        internal.ReadQueryString(r, "name", ""),
 		Description: internal.ReadQueryString(r, "description", ""),
-		Currency:    internal.ReadQueryString(r, "currency", ""),
-		Sort:        internal.ReadQueryString(r, "sort", "id"),
+		Currency:    internal.ReadQueryCSV(r, "currency", nil),
+		Filter:      internal.ReadQueryString(r, "filter", ""),
+		Sort:        internal.ReadQueryCSV(r, "sort", nil),
 	}
+	span.End()
+
+	_, span = tracing.Tracer().Start(r.Context(), "validate query")
+	errors := filters.ValidateGroupQuery(val)
+	span.End()
 
-	if errors := filters.ValidateGroupQuery(val); errors != nil {
+	if errors != nil {
 		internal.BadRequestError(w, r, errors)
 		return
 	}
 
-	data, meta, err := app.Models.Groups.GetAll(&filters)
+	switch internal.Negotiate(r.Header.Get("Accept"), groupRepresentations...) {
+	case contentTypeCSV:
+		app.streamGroups(w, r, &filters, contentTypeCSV, newCSVGroupEncoder(w))
+		return
+	case contentTypeNDJSON, "application/ndjson":
+		app.streamGroups(w, r, &filters, contentTypeNDJSON, newNDJSONGroupEncoder(w))
+		return
+	case "":
+		internal.NotAcceptableError(w, r, groupSingleRepresentations)
+		return
+	}
+
+	data, meta, err := app.Models.Groups.GetAll(r.Context(), &filters)
 	if err != nil {
 		internal.InternalServerError(w, r, err)
 		return
 	}
 
+	_, span = tracing.Tracer().Start(r.Context(), "encode response")
+	defer span.End()
+
 	internal.WriteJSON(w, http.StatusOK, map[string]any{
 		"metadata": meta,
 		"data":     data,
 	})
 }
+
+// insertGroup creates the group and the owner membership of its creator
+// atomically.
+func (app *application) insertGroup(ctx context.Context, group *model.Group) error {
+	if err := createGroup(ctx, app.Models, group); err != nil {
+		return err
+	}
+
+	app.Metrics.GroupCreated()
+	return nil
+}
+
+// createGroup creates the group and the owner membership of its creator in a
+// transaction on models, which joins the transaction models is bound to.
+func createGroup(ctx context.Context, models *repository.Models, group *model.Group) error {
+	return models.WithTx(ctx, func(tx *repository.Models) error {
+		if err := tx.Groups.Insert(ctx, group); err != nil {
+			return err
+		}
+
+		owner := model.Member{
+			GroupId: group.Id,
+			UserId:  group.CreatedBy,
+			Role:    model.MemberRoleOwner,
+		}
+		return tx.Members.Insert(ctx, &owner)
+	})
+}
+
+// replaceGroup saves every field of the validated group over the current
+// version of the group with the same id.
+func (app *application) replaceGroup(ctx context.Context, group *model.Group) error {
+	err := overwriteGroup(ctx, app.Models, group)
+
+	if errors.Is(err, internal.ErrEditConflict) {
+		app.Metrics.UpdateConflict()
+	}
+	return err
+}
+
+// overwriteGroup saves the validated group over the current version of the
+// group with the same id in a transaction on models, which joins the
+// transaction models is bound to.
+func overwriteGroup(ctx context.Context, models *repository.Models, group *model.Group) error {
+	// a full replacement applies to whatever version is current; the current
+	// version is read with the row locked so no other write can slip in
+	// between the read and the update.
+	return models.WithTx(ctx, func(tx *repository.Models) error {
+		current, err := tx.Groups.GetForUpdate(ctx, group.Id)
+		if err != nil {
+			return err
+		}
+
+		group.Version = current.Version
+		return tx.Groups.Update(ctx, group)
+	})
+}
+
+// patchGroup applies the non-nil fields of input to the group identified by id,
+// validates it with val and saves it. It returns the validation errors instead
+// of saving when the patched group is invalid.
+func (app *application) patchGroup(ctx context.Context, id int, input model.GroupUpdate, val *validation.Validator) (*model.Group, map[string]string, error) {
+	// read and write the group in one transaction; the version check in Update
+	// rejects the patch if the group changed after it was read.
+	var group *model.Group
+	var val_errors map[string]string
+
+	err := app.Models.WithTx(ctx, func(tx *repository.Models) error {
+		var err error
+		group, err = tx.Groups.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if input.Name != nil {
+			group.Name = *input.Name
+		}
+
+		if input.Description != nil {
+			group.Description = *input.Description
+		}
+
+		if input.Currency != nil {
+			group.Currency = *input.Currency
+		}
+
+		val_errors = group.Validate(val)
+		if val_errors != nil {
+			return nil
+		}
+
+		return tx.Groups.Update(ctx, group)
+	})
+
+	if errors.Is(err, internal.ErrEditConflict) {
+		app.Metrics.UpdateConflict()
+	}
+	return group, val_errors, err
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// BatchGroupsHandler handles POST /v1/groups:batch. It applies a batch of
+// create, update and delete operations, each validated with Group.Validate
+// like the request it stands for, and reports the status each request would
+// have been answered with.
+//
+// An atomic batch runs every operation in a single transaction. It is answered
+// with 200 when all of them succeed, or with the status of the failing
+// operations, every other operation being reported as 424 Failed Dependency. A
+// best-effort batch applies each valid operation on its own and is answered
+// with 200.
+func (app *application) BatchGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	batch := model.GroupBatch{}
+
+	if err := internal.ReadJSON(w, r, &batch); err != nil {
+		internal.BadRequestError(w, r, err.Error())
+		return
+	}
+
+	val := internal.NewValidator(r)
+	if errors := batch.ValidateGroupBatch(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	lang := i18n.FromContext(r.Context())
+
+	res := model.GroupBatchResponse{
+		Atomic:  batch.Atomic,
+		Results: make([]model.GroupBatchResult, len(batch.Operations)),
+	}
+
+	for i, op := range batch.Operations {
+		res.Results[i] = model.GroupBatchResult{Index: i, Op: op.Op}
+
+		if errors := op.Validate(validation.NewLocalized(lang)); errors != nil {
+			res.Results[i].Status = http.StatusBadRequest
+			res.Results[i].Error = errors
+		}
+	}
+
+	status := http.StatusOK
+	if batch.Atomic {
+		var err error
+		status, err = app.applyAtomicBatch(r.Context(), batch.Operations, res.Results)
+		if err != nil {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+	} else {
+		app.applyBatch(r.Context(), batch.Operations, res.Results)
+	}
+
+	for _, result := range res.Results {
+		if result.Status < http.StatusBadRequest {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+
+	internal.WriteJSON(w, status, res)
+}
+
+// applyBatch applies each valid operation in its own transaction, recording
+// its outcome in results. Operations already failed are skipped.
+func (app *application) applyBatch(ctx context.Context, ops []model.GroupBatchOperation, results []model.GroupBatchResult) {
+	lang := i18n.FromContext(ctx)
+
+	for i := range ops {
+		if results[i].Status != 0 {
+			continue
+		}
+
+		group, err := applyGroupOperation(ctx, app.Models, &ops[i])
+		if err != nil {
+			results[i].Status, results[i].Error = batchError(lang, err)
+			if results[i].Status == http.StatusInternalServerError {
+				internal.NewLogger().Log.Error().Err(err).Int("index", i).Msg("internal server error")
+			}
+			if errors.Is(err, internal.ErrEditConflict) {
+				app.Metrics.UpdateConflict()
+			}
+			continue
+		}
+
+		results[i].Status, results[i].Group = batchStatus(ops[i].Op), group
+		if ops[i].Op == model.BatchCreate {
+			app.Metrics.GroupCreated()
+		}
+	}
+}
+
+// applyAtomicBatch applies every operation in a single transaction when all of
+// them are valid, recording their outcome in results. It returns the status of
+// the response: 200 when the transaction committed, otherwise that of the
+// failed operations, the others being marked as not applied. Errors without a
+// status of their own are returned.
+func (app *application) applyAtomicBatch(ctx context.Context, ops []model.GroupBatchOperation, results []model.GroupBatchResult) (int, error) {
+	lang := i18n.FromContext(ctx)
+
+	failed := slices.IndexFunc(results, func(result model.GroupBatchResult) bool { return result.Status != 0 })
+	if failed < 0 {
+		err := app.Models.WithTx(ctx, func(tx *repository.Models) error {
+			// the transaction is run again after a serialization failure
+			failed = -1
+			for i := range ops {
+				group, err := applyGroupOperation(ctx, tx, &ops[i])
+				if err != nil {
+					failed = i
+					return err
+				}
+				results[i].Status, results[i].Group = batchStatus(ops[i].Op), group
+			}
+			return nil
+		})
+
+		if errors.Is(err, internal.ErrEditConflict) {
+			app.Metrics.UpdateConflict()
+		}
+
+		// beginning or committing the transaction failed
+		if err != nil && failed < 0 {
+			return 0, err
+		}
+
+		if err != nil {
+			results[failed].Status, results[failed].Error = batchError(lang, err)
+			if results[failed].Status == http.StatusInternalServerError {
+				return 0, err
+			}
+		}
+	}
+
+	if failed < 0 {
+		for _, op := range ops {
+			if op.Op == model.BatchCreate {
+				app.Metrics.GroupCreated()
+			}
+		}
+		return http.StatusOK, nil
+	}
+
+	// nothing was applied: keep the failures and report the rest as depending on them
+	status := results[failed].Status
+	for i := range results {
+		if results[i].Status < http.StatusBadRequest {
+			results[i].Status = http.StatusFailedDependency
+			results[i].Group = nil
+			results[i].Error = i18n.T(lang, "error.batch_not_applied", i18n.Args{"index": failed})
+		}
+	}
+
+	return status, nil
+}
+
+// applyGroupOperation applies op on models, returning the group it created or
+// updated.
+func applyGroupOperation(ctx context.Context, models *repository.Models, op *model.GroupBatchOperation) (*model.Group, error) {
+	switch op.Op {
+	case model.BatchCreate:
+		group := op.NewGroup()
+		return group, createGroup(ctx, models, group)
+	case model.BatchUpdate:
+		group := op.NewGroup()
+		return group, overwriteGroup(ctx, models, group)
+	default:
+		return nil, models.Groups.DeleteGroup(ctx, op.Id)
+	}
+}
+
+// batchStatus returns the status of a successful operation of kind op.
+func batchStatus(op string) int {
+	if op == model.BatchCreate {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// batchError returns the status and message of an operation failing with err,
+// as the request it stands for would have been answered.
+func batchError(lang string, err error) (int, string) {
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+		return http.StatusNotFound, i18n.T(lang, "error.not_found", nil)
+	case errors.Is(err, internal.ErrEditConflict):
+		return http.StatusConflict, i18n.T(lang, "error.edit_conflict", nil)
+	default:
+		return http.StatusInternalServerError, i18n.T(lang, "error.internal", nil)
+	}
+}
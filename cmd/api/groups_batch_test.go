@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// postBatch sends the batch to the router of an application backed by a mock
+// database and returns the status and body of the response.
+func postBatch(t *testing.T, setup func(mock sqlmock.Sqlmock), batch string) (int, model.GroupBatchResponse) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	setup(mock)
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+	app.Config.BatchMaxBodySize = 1 << 20
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/groups:batch", strings.NewReader(batch))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "Bearer "+testToken(t, "1", false))
+
+	w := httptest.NewRecorder()
+	app.Router().ServeHTTP(w, r)
+
+	res := model.GroupBatchResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decode %s: %v", w.Body, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	return w.Code, res
+}
+
+// expectDeletion expects the locked group to be deleted with its audit event,
+// activity and the webhook deliveries of the deletion.
+func expectDeletion(mock sqlmock.Sqlmock, id int) {
+	mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO activities`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, "2026-01-01T00:00:00Z"))
+	mock.ExpectQuery(`INSERT INTO outbox`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, "2026-01-01T00:00:00Z"))
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_notify`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM groups`).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// checkStatuses fails the test unless the results have the statuses want.
+func checkStatuses(t *testing.T, res model.GroupBatchResponse, want ...int) {
+	t.Helper()
+
+	if len(res.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(res.Results), len(want))
+	}
+	for i, result := range res.Results {
+		if result.Status != want[i] {
+			t.Errorf("result %d: status %d, want %d (error %v)", i, result.Status, want[i], result.Error)
+		}
+		if result.Status == http.StatusFailedDependency && (result.Group != nil || result.Error == nil) {
+			t.Errorf("result %d: not applied but reported as %+v", i, result)
+		}
+	}
+}
+
+func TestAtomicBatchInvalidOperation(t *testing.T) {
+	status, res := postBatch(t, func(mock sqlmock.Sqlmock) {}, `{"atomic": true, "operations": [
+		{"op": "create", "group": {"name": "Trip", "currency": "Euro", "created_by": 1}},
+		{"op": "create", "group": {"name": "Trip", "currency": "Yen", "created_by": 1}}
+	]}`)
+
+	if status != http.StatusBadRequest {
+		t.Errorf("status %d, want %d", status, http.StatusBadRequest)
+	}
+	checkStatuses(t, res, http.StatusFailedDependency, http.StatusBadRequest)
+
+	if res.Succeeded != 0 || res.Failed != 2 {
+		t.Errorf("succeeded %d and failed %d, want 0 and 2", res.Succeeded, res.Failed)
+	}
+}
+
+func TestAtomicBatchRollsBackOnFailure(t *testing.T) {
+	status, res := postBatch(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO groups`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "version"}).AddRow(3, "2026-01-01T00:00:00Z", 1))
+		mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectActivity(mock)
+		mock.ExpectQuery(`INSERT INTO group_members`).
+			WillReturnRows(sqlmock.NewRows([]string{"joined_at"}).AddRow("2026-01-01T00:00:00Z"))
+		mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+		expectActivity(mock)
+
+		// the group to delete does not exist
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(9).WillReturnRows(sqlmock.NewRows(groupColumns))
+		mock.ExpectRollback()
+	}, `{"atomic": true, "operations": [
+		{"op": "create", "group": {"name": "Trip", "currency": "Euro", "created_by": 1}},
+		{"op": "delete", "id": 9}
+	]}`)
+
+	if status != http.StatusNotFound {
+		t.Errorf("status %d, want %d", status, http.StatusNotFound)
+	}
+	checkStatuses(t, res, http.StatusFailedDependency, http.StatusNotFound)
+}
+
+func TestAtomicBatchRetriesSerializationFailure(t *testing.T) {
+	status, res := postBatch(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(3).WillReturnError(&pq.Error{Code: "40001"})
+		mock.ExpectRollback()
+
+		// the whole transaction is run again
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+		expectDeletion(mock, 3)
+		mock.ExpectCommit()
+	}, `{"atomic": true, "operations": [{"op": "delete", "id": 3}]}`)
+
+	if status != http.StatusOK {
+		t.Errorf("status %d, want %d", status, http.StatusOK)
+	}
+	checkStatuses(t, res, http.StatusOK)
+}
+
+func TestBestEffortBatchReportsEachOperation(t *testing.T) {
+	status, res := postBatch(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(9).WillReturnRows(sqlmock.NewRows(groupColumns))
+		mock.ExpectRollback()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+		expectDeletion(mock, 3)
+		mock.ExpectCommit()
+	}, `{"atomic": false, "operations": [{"op": "delete", "id": 9}, {"op": "delete", "id": 3}]}`)
+
+	if status != http.StatusOK {
+		t.Errorf("status %d, want %d", status, http.StatusOK)
+	}
+	checkStatuses(t, res, http.StatusNotFound, http.StatusOK)
+
+	if res.Succeeded != 1 || res.Failed != 1 {
+		t.Errorf("succeeded %d and failed %d, want 1 and 1", res.Succeeded, res.Failed)
+	}
+}
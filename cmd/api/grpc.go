@@ -0,0 +1,192 @@
+package main
+
+//go:generate protoc -I ../../proto --go_out=../../pkg/pb --go_opt=paths=source_relative --go-grpc_out=../../pkg/pb --go-grpc_opt=paths=source_relative fairshare/v1/group_service.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/auth"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	fairsharev1 "github.com/Abdul4code/FairShare/pkg/pb/fairshare/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer returns the gRPC server of the API, serving fairshare.v1.GroupService
+// for other backend services on a separate port from the HTTP API.
+//
+// Every call goes through the interceptors in order: requestIDUnary assigns
+// the request id, logUnary logs the outcome, localizeUnary picks the language
+// of error messages, recoverUnary turns panics into INTERNAL errors, authUnary
+// verifies the bearer token of the user, and statusUnary converts the errors
+// of the service to gRPC statuses.
+func (app *application) GRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		app.requestIDUnary,
+		app.logUnary(internal.NewLogger()),
+		app.localizeUnary,
+		app.recoverUnary,
+		app.authUnary,
+		app.statusUnary,
+	))
+
+	fairsharev1.RegisterGroupServiceServer(server, &groupServer{app: app})
+
+	return server
+}
+
+// incomingValue returns the first value of the metadata key sent with the
+// call of ctx, or "" when there is none.
+func incomingValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestIDUnary attaches the x-request-id metadata sent by the client, or a
+// random id when there is none or it is not valid, to the context and the
+// response headers of the call, like the requestID middleware.
+func (app *application) requestIDUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	id, err := requestIDOrNew(incomingValue(ctx, "x-request-id"))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id))
+	return handler(internal.ContextWithRequestID(ctx, id), req)
+}
+
+// logUnary returns an interceptor logging the method, status code, duration
+// and request id of every call with logger.
+func (app *application) logUnary(logger *internal.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		event := logger.Log.Info()
+		if status.Code(err) == codes.Internal || status.Code(err) == codes.Unknown {
+			event = logger.Log.Error()
+		}
+
+		event.
+			Str("method", info.FullMethod).
+			Str("code", status.Code(err).String()).
+			Dur("duration", time.Since(start)).
+			Str("request_id", internal.ContextGetRequestID(ctx)).
+			Msg("grpc call")
+
+		return res, err
+	}
+}
+
+// localizeUnary selects the language of error messages from the
+// accept-language metadata, like the localize middleware.
+func (app *application) localizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	lang := i18n.Match(incomingValue(ctx, "accept-language"))
+	return handler(i18n.WithLanguage(ctx, lang), req)
+}
+
+// recoverUnary recovers from a panic of the rest of the chain, logs it with
+// its stack and reports the call as INTERNAL instead of crashing the server.
+func (app *application) recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			internal.NewLogger().Log.Error().
+				Str("method", info.FullMethod).
+				Str("panic", fmt.Sprint(p)).
+				Bytes("stack", debug.Stack()).
+				Msg("grpc call panicked")
+
+			res, err = nil, status.Error(codes.Internal, i18n.T(i18n.FromContext(ctx), "error.internal", nil))
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// authUnary authenticates every call like the authenticate middleware, with a
+// bearer token in the authorization metadata verified against the same
+// secret, and stores the user it was issued to on the context. Unlike HTTP
+// requests, calls cannot be anonymous: calls without a valid token are
+// rejected.
+func (app *application) authUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	lang := i18n.FromContext(ctx)
+
+	token, ok := bearerToken(incomingValue(ctx, "authorization"))
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, i18n.T(lang, "error.unauthorized", nil))
+	}
+
+	claims, err := auth.Verify(app.Config.AuthSecret, token, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, i18n.T(lang, "error.invalid_token", nil))
+	}
+
+	// Verify only accepts claims identifying a user
+	actor, _ := claims.UserId()
+	ctx = internal.ContextWithActor(ctx, actor)
+	if claims.Admin {
+		ctx = internal.ContextWithAdmin(ctx)
+	}
+
+	return handler(ctx, req)
+}
+
+// statusUnary converts the errors returned by the service to gRPC statuses
+// with the messages of the matching REST errors: internal.ErrNotFound becomes
+// NOT_FOUND, internal.ErrEditConflict ABORTED and invalid fields
+// INVALID_ARGUMENT with a BadRequest detail listing them. Other errors are
+// logged and reported as INTERNAL.
+func (app *application) statusUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	res, err := handler(ctx, req)
+	if err == nil {
+		return res, nil
+	}
+
+	lang := i18n.FromContext(ctx)
+
+	var fields validationErrors
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+		return nil, status.Error(codes.NotFound, i18n.T(lang, "error.not_found", nil))
+	case errors.Is(err, internal.ErrEditConflict):
+		return nil, status.Error(codes.Aborted, i18n.T(lang, "error.edit_conflict", nil))
+	case errors.As(err, &fields):
+		detail := &errdetails.BadRequest{}
+		for _, field := range slices.Sorted(maps.Keys(fields)) {
+			detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: fields[field],
+			})
+		}
+
+		st, detailErr := status.New(codes.InvalidArgument, i18n.T(lang, "error.invalid_request", nil)).WithDetails(detail)
+		if detailErr != nil {
+			return nil, status.Error(codes.InvalidArgument, fields.Error())
+		}
+		return nil, st.Err()
+	case errors.Is(err, context.Canceled):
+		return nil, status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	internal.NewLogger().Log.Error().Err(err).Str("method", info.FullMethod).Msg("internal server error")
+	return nil, status.Error(codes.Internal, i18n.T(lang, "error.internal", nil))
+}
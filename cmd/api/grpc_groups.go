@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/validation"
+	fairsharev1 "github.com/Abdul4code/FairShare/pkg/pb/fairshare/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// groupServer implements fairshare.v1.GroupService with the same models,
+// validation and transactions as the /v1/groups routes. Its errors are
+// converted to gRPC statuses by statusUnary.
+type groupServer struct {
+	fairsharev1.UnimplementedGroupServiceServer
+
+	app *application
+}
+
+// patchableFields lists the fields an update mask of PatchGroup may name.
+var patchableFields = []string{"name", "currency", "description"}
+
+// CreateGroup creates a group like POST /v1/groups.
+func (s *groupServer) CreateGroup(ctx context.Context, req *fairsharev1.CreateGroupRequest) (*fairsharev1.Group, error) {
+	group := groupFromProto(req.GetGroup())
+
+	if errs := group.Validate(validation.NewLocalized(i18n.FromContext(ctx))); errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	if err := s.app.insertGroup(ctx, &group); err != nil {
+		return nil, err
+	}
+
+	return groupToProto(&group), nil
+}
+
+// GetGroup returns a group like GET /v1/groups/:id.
+func (s *groupServer) GetGroup(ctx context.Context, req *fairsharev1.GetGroupRequest) (*fairsharev1.Group, error) {
+	group, err := s.app.Models.Groups.Get(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return groupToProto(group), nil
+}
+
+// UpdateGroup replaces a group like PUT /v1/groups/:id.
+func (s *groupServer) UpdateGroup(ctx context.Context, req *fairsharev1.UpdateGroupRequest) (*fairsharev1.Group, error) {
+	group := groupFromProto(req.GetGroup())
+
+	if errs := group.Validate(validation.NewLocalized(i18n.FromContext(ctx))); errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	if err := s.app.replaceGroup(ctx, &group); err != nil {
+		return nil, err
+	}
+
+	return groupToProto(&group), nil
+}
+
+// PatchGroup changes the fields of a group named by the update mask like
+// PATCH /v1/groups/:id. Without a mask, the fields set to a non-default value
+// are changed.
+func (s *groupServer) PatchGroup(ctx context.Context, req *fairsharev1.PatchGroupRequest) (*fairsharev1.Group, error) {
+	val := validation.NewLocalized(i18n.FromContext(ctx))
+	patch := req.GetGroup()
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		for _, field := range patchableFields {
+			if patchValue(patch, field) != "" {
+				paths = append(paths, field)
+			}
+		}
+	}
+
+	input := model.GroupUpdate{}
+	for _, path := range paths {
+		value := patchValue(patch, path)
+
+		switch path {
+		case "name":
+			input.Name = &value
+		case "currency":
+			input.Currency = &value
+		case "description":
+			input.Description = &value
+		default:
+			val.AddCode("update_mask", "validation.oneof", i18n.Args{"field": "update_mask", "param": "name, currency, description"})
+			return nil, validationErrors(val.Errors)
+		}
+	}
+
+	group, errs, err := s.app.patchGroup(ctx, int(patch.GetId()), input, val)
+	if err != nil {
+		return nil, err
+	}
+	if errs != nil {
+		return nil, validationErrors(errs)
+	}
+
+	return groupToProto(group), nil
+}
+
+// DeleteGroup deletes a group like DELETE /v1/groups/:id.
+func (s *groupServer) DeleteGroup(ctx context.Context, req *fairsharev1.DeleteGroupRequest) (*fairsharev1.DeleteGroupResponse, error) {
+	if err := s.app.Models.Groups.DeleteGroup(ctx, int(req.GetId())); err != nil {
+		return nil, err
+	}
+
+	return &fairsharev1.DeleteGroupResponse{}, nil
+}
+
+// ListGroups lists groups with the filters and sort order of GET /v1/groups.
+// Its page tokens hold the offset of the last group of a page, like the
+// cursors of the groups GraphQL connection.
+func (s *groupServer) ListGroups(ctx context.Context, req *fairsharev1.ListGroupsRequest) (*fairsharev1.ListGroupsResponse, error) {
+	val := validation.NewLocalized(i18n.FromContext(ctx))
+
+	filters := model.GroupQuery{
+		Name:        req.GetName(),
+		Currency:    req.GetCurrency(),
+		Description: req.GetDescription(),
+		Filter:      req.GetFilter(),
+		Sort:        internal.SplitCSV(req.GetOrderBy()),
+		Page:        1,
+		PageSize:    int(req.GetPageSize()),
+	}
+
+	if filters.PageSize == 0 {
+		filters.PageSize = 10
+	}
+
+	if token := req.GetPageToken(); token != "" {
+		offset, ok := decodeCursor("group", token)
+		if !ok {
+			val.AddCode("page_token", "query.cursor", i18n.Args{"field": "page_token"})
+		}
+		filters.Offset = int(offset) + 1
+	}
+
+	if errs := filters.ValidateGroupQuery(val); errs != nil {
+		for field, message := range errs {
+			if rest, ok := strings.CutPrefix(field, "sort"); ok {
+				errs["order_by"+rest] = message
+				delete(errs, field)
+			}
+		}
+		return nil, validationErrors(errs)
+	}
+
+	groups, meta, err := s.app.Models.Groups.GetAll(ctx, &filters)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &fairsharev1.ListGroupsResponse{TotalSize: int32(meta.Total)}
+	for _, group := range groups {
+		res.Groups = append(res.Groups, groupToProto(group))
+	}
+
+	if last := filters.Offset + len(groups); last < meta.Total {
+		res.NextPageToken = encodeCursor("group", int64(last-1))
+	}
+
+	return res, nil
+}
+
+// patchValue returns the value of the field of group named by path, or "" when
+// path names no patchable field.
+func patchValue(group *fairsharev1.Group, path string) string {
+	switch path {
+	case "name":
+		return group.GetName()
+	case "currency":
+		return group.GetCurrency()
+	case "description":
+		return group.GetDescription()
+	}
+	return ""
+}
+
+// groupFromProto returns the group described by a message.
+func groupFromProto(group *fairsharev1.Group) model.Group {
+	return model.Group{
+		Id:          int(group.GetId()),
+		Name:        group.GetName(),
+		Currency:    group.GetCurrency(),
+		Description: group.GetDescription(),
+		CreatedBy:   int(group.GetCreatedBy()),
+	}
+}
+
+// groupToProto returns the message describing group.
+func groupToProto(group *model.Group) *fairsharev1.Group {
+	message := &fairsharev1.Group{
+		Id:          int64(group.Id),
+		Name:        group.Name,
+		Currency:    group.Currency,
+		Description: group.Description,
+		CreatedBy:   int64(group.CreatedBy),
+		Version:     int32(group.Version),
+	}
+
+	// created_at is scanned into a string in the RFC 3339 format
+	if createdAt, err := time.Parse(time.RFC3339Nano, group.CreatedAt); err == nil {
+		message.CreateTime = timestamppb.New(createdAt)
+	}
+
+	return message
+}
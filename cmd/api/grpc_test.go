@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/repository"
+	fairsharev1 "github.com/Abdul4code/FairShare/pkg/pb/fairshare/v1"
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// newGRPCTest serves the gRPC server of app over an in-memory connection and
+// returns a client of it.
+func newGRPCTest(t *testing.T, app *application) fairsharev1.GroupServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := app.GRPCServer()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return fairsharev1.NewGroupServiceClient(conn)
+}
+
+// newGRPCMockTest returns a client of the gRPC server of an application backed
+// by a mock database, failing the test when expectations are left unmet.
+func newGRPCMockTest(t *testing.T) (fairsharev1.GroupServiceClient, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return newGRPCTest(t, app), mock
+}
+
+// withToken returns a context sending the bearer token of user 1.
+func withToken(t *testing.T) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testToken(t, "1", false))
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	client := newGRPCTest(t, newTestApp())
+
+	tests := map[string]context.Context{
+		"no token":      context.Background(),
+		"not a bearer":  metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic dXNlcg=="),
+		"invalid token": metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid"),
+	}
+
+	for name, ctx := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.GetGroup(ctx, &fairsharev1.GetGroupRequest{Id: 3})
+			if code := status.Code(err); code != codes.Unauthenticated {
+				t.Errorf("code %s, want %s", code, codes.Unauthenticated)
+			}
+		})
+	}
+}
+
+func TestGRPCStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		call   func(ctx context.Context, client fairsharev1.GroupServiceClient) error
+		expect func(mock sqlmock.Sqlmock)
+		code   codes.Code
+		fields []string
+	}{
+		{"not found", func(ctx context.Context, client fairsharev1.GroupServiceClient) error {
+			_, err := client.GetGroup(ctx, &fairsharev1.GetGroupRequest{Id: 3})
+			return err
+		}, func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM groups WHERE id`).WithArgs(3).WillReturnRows(sqlmock.NewRows(groupColumns))
+		}, codes.NotFound, nil},
+		{"edit conflict", func(ctx context.Context, client fairsharev1.GroupServiceClient) error {
+			_, err := client.PatchGroup(ctx, &fairsharev1.PatchGroupRequest{
+				Group:      &fairsharev1.Group{Id: 3, Name: "Lisbon"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			})
+			return err
+		}, func(mock sqlmock.Sqlmock) {
+			// the group changes between the read and the locked read of the update
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FROM groups WHERE id = \$1;`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+			mock.ExpectQuery(`FOR UPDATE`).WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(groupColumns).AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 2))
+			mock.ExpectRollback()
+		}, codes.Aborted, nil},
+		{"invalid fields", func(ctx context.Context, client fairsharev1.GroupServiceClient) error {
+			_, err := client.CreateGroup(ctx, &fairsharev1.CreateGroupRequest{Group: &fairsharev1.Group{Currency: "Yen"}})
+			return err
+		}, nil, codes.InvalidArgument, []string{"currency", "name"}},
+		{"unknown update mask path", func(ctx context.Context, client fairsharev1.GroupServiceClient) error {
+			_, err := client.PatchGroup(ctx, &fairsharev1.PatchGroupRequest{
+				Group:      &fairsharev1.Group{Id: 3, Name: "Lisbon"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "created_by"}},
+			})
+			return err
+		}, nil, codes.InvalidArgument, []string{"update_mask"}},
+		{"invalid page token", func(ctx context.Context, client fairsharev1.GroupServiceClient) error {
+			_, err := client.ListGroups(ctx, &fairsharev1.ListGroupsRequest{PageToken: "nonsense"})
+			return err
+		}, nil, codes.InvalidArgument, []string{"page_token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock := newGRPCMockTest(t)
+			if tt.expect != nil {
+				tt.expect(mock)
+			}
+
+			err := tt.call(withToken(t), client)
+
+			st := status.Convert(err)
+			if st.Code() != tt.code {
+				t.Fatalf("code %s (%s), want %s", st.Code(), st.Message(), tt.code)
+			}
+
+			fields := []string{}
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, violation := range badRequest.GetFieldViolations() {
+						fields = append(fields, violation.GetField())
+					}
+				}
+			}
+			if tt.fields != nil && !slices.Equal(fields, tt.fields) {
+				t.Errorf("field violations %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestGRPCRecoversPanics(t *testing.T) {
+	// the models of the test application have no database, so reading a
+	// group panics
+	client := newGRPCTest(t, newTestApp())
+
+	_, err := client.GetGroup(withToken(t), &fairsharev1.GetGroupRequest{Id: 3})
+	if code := status.Code(err); code != codes.Internal {
+		t.Fatalf("code %s, want %s", code, codes.Internal)
+	}
+
+	// the server keeps serving after the panic
+	_, err = client.GetGroup(withToken(t), &fairsharev1.GetGroupRequest{Id: 0})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("code %s, want %s", code, codes.NotFound)
+	}
+}
@@ -1,13 +1,135 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"runtime/debug"
+	"time"
 
 	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/migrations"
 )
 
+// version and commit identify the build. They are set at link time with
+// -ldflags "-X main.version=... -X main.commit=...".
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// component statuses reported by the readiness probe
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+// componentStatus describes the health of a dependency checked by the readiness probe.
+type componentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
 // create a health check handler
 func (app *application) healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	message := map[string]string{"status": "available", "environment": app.Config.Env}
 	internal.WriteJSON(w, http.StatusOK, message)
 }
+
+// liveHandler handles GET /v1/health/live. It reports that the process is up and
+// serving requests without checking any dependency.
+func (app *application) liveHandler(w http.ResponseWriter, r *http.Request) {
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"status": "alive",
+		"build":  buildInfo(),
+		"uptime": app.uptime(),
+	})
+}
+
+// readyHandler handles GET /v1/health/ready. It checks the database answers
+// queries and runs the schema this binary expects, and reports 503 when a
+// check fails or the server is shutting down.
+func (app *application) readyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	components := map[string]componentStatus{
+		"database": app.checkDatabase(ctx),
+		"schema":   app.checkSchema(ctx),
+	}
+
+	ready := true
+	for _, component := range components {
+		if component.Status != statusUp {
+			ready = false
+		}
+	}
+
+	status, code := "ready", http.StatusOK
+	switch {
+	case app.shuttingDown.Load():
+		status, code = "shutting_down", http.StatusServiceUnavailable
+	case !ready:
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	internal.WriteJSON(w, code, map[string]any{
+		"status":      status,
+		"environment": app.Config.Env,
+		"components":  components,
+		"build":       buildInfo(),
+		"uptime":      app.uptime(),
+	})
+}
+
+// checkDatabase pings the database.
+func (app *application) checkDatabase(ctx context.Context) componentStatus {
+	if err := app.Models.Health.Ping(ctx); err != nil {
+		return componentStatus{Status: statusDown, Error: err.Error()}
+	}
+	return componentStatus{Status: statusUp}
+}
+
+// checkSchema compares the migration version of the database with the newest
+// migration embedded in the binary.
+func (app *application) checkSchema(ctx context.Context) componentStatus {
+	expected, err := migrations.Latest()
+	if err != nil {
+		return componentStatus{Status: statusDown, Error: err.Error()}
+	}
+
+	current, dirty, err := app.Models.Health.SchemaVersion(ctx)
+	switch {
+	case err != nil:
+		return componentStatus{Status: statusDown, Error: err.Error()}
+	case dirty:
+		return componentStatus{Status: statusDown, Error: fmt.Sprintf("migration %d is dirty", current)}
+	case current != expected:
+		return componentStatus{Status: statusDown, Error: fmt.Sprintf("schema version is %d, expected %d", current, expected)}
+	}
+
+	return componentStatus{Status: statusUp}
+}
+
+// uptime returns how long the application has been running, in whole seconds.
+func (app *application) uptime() string {
+	return time.Since(app.started).Truncate(time.Second).String()
+}
+
+// buildInfo returns the version and commit of the binary. Without a commit set
+// at link time, the VCS revision stamped by the Go toolchain is used.
+func buildInfo() map[string]string {
+	info := map[string]string{"version": version, "commit": commit}
+
+	if commit == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				if setting.Key == "vcs.revision" {
+					info["commit"] = setting.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
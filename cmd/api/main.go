@@ -1,26 +1,101 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/auth"
+	"github.com/Abdul4code/FairShare/internal/events"
+	"github.com/Abdul4code/FairShare/internal/metrics"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/outbox"
+	"github.com/Abdul4code/FairShare/internal/ratelimit"
 	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/Abdul4code/FairShare/internal/tracing"
+	"github.com/Abdul4code/FairShare/internal/webhooks"
 	"github.com/joho/godotenv"
 )
 
 // config stores the configuration of the API
 type config struct {
-	Addr string               // Network port address (e.g., :4000)
-	Env  string               // Application run environment: development | staging | production
-	DB   repository.DB_Config // configurations for the database connection pool
+	Addr      string               // Network port address (e.g., :4000)
+	AdminAddr string               // Network port address of the admin server exposing /metrics, disabled when empty
+	Env       string               // Application run environment: development | staging | production
+	DB        repository.DB_Config // configurations for the database connection pool
+
+	AuthSecret auth.Secret // key the bearer tokens of users are verified with; every token is rejected when empty
+
+	OutboxFile string // optional file every published outbox message is appended to
+
+	Tracing tracing.Config // span exporter settings
+
+	RateLimit rateLimitConfig // request limits per route group
+
+	CORS corsConfig // cross-origin requests allowed from browsers
+
+	GraphQL graphQLConfig // limits of queries sent to /v1/graphql
+
+	GRPC grpcConfig // gRPC server used by other backend services
+
+	BatchMaxBodySize int64 // largest body of a batch request in bytes
+
+	ShutdownDrain time.Duration // time between failing readiness and closing the listeners on shutdown
+}
+
+// grpcConfig stores the settings of the gRPC server
+type grpcConfig struct {
+	Addr string // Network port address of the gRPC server, disabled when empty; calls are authenticated with the tokens of AuthSecret
+}
+
+// graphQLConfig stores the limits GraphQL queries are checked against before
+// they are executed
+type graphQLConfig struct {
+	MaxComplexity int // highest cost of a query, counting every field once per item of the lists it is in
+	MaxDepth      int // deepest nesting of fields in a query
+}
+
+// corsConfig stores which cross-origin requests browsers may make to the API
+type corsConfig struct {
+	Origins     []string      // trusted origins, e.g. https://app.example.com, or * for any; CORS is disabled when empty
+	Methods     []string      // methods cross-origin requests may use
+	Headers     []string      // request headers cross-origin requests may send
+	Credentials bool          // whether cross-origin requests may include cookies and credentials; not allowed with *
+	MaxAge      time.Duration // how long browsers may cache a preflight response
+}
+
+// rateLimitConfig stores the rate limiting settings of the API
+type rateLimitConfig struct {
+	Enabled  bool                        // whether requests are rate limited at all
+	Store    string                      // where token buckets are kept: memory | postgres
+	Policies map[string]ratelimit.Policy // limits per route group (read, write, realtime)
+}
+
+// defaultRateLimits holds the limits of each route group unless configured otherwise
+var defaultRateLimits = map[string]string{
+	rateLimitRead:     "anon=5:20,user=20:60",
+	rateLimitWrite:    "anon=1:5,user=5:20",
+	rateLimitRealtime: "anon=0.2:5,user=1:10",
 }
 
 // application holds dependencies for the API (configuration, modules, etc.)
 type application struct {
-	Config config
-	Models *repository.Models
+	Config  config
+	Models  *repository.Models
+	Hub     *events.Hub // fans out group change notifications to event streams
+	Metrics *metrics.Metrics
+	Limiter ratelimit.Store // token buckets of the rate limiter, nil when rate limiting is disabled
+
+	started      time.Time   // when the application started, reported as uptime
+	shuttingDown atomic.Bool // set once graceful shutdown begins; fails the readiness probe
+
+	workerState
 }
 
 func main() {
@@ -28,39 +103,92 @@ func main() {
 	godotenv.Load()
 
 	// read database settings from environment
-	dsn, err := internal.GetString("dsn")
+	dbConfig, err := repository.ConfigFromEnv()
+	if err != nil {
+		internal.NewLogger().Log.Panic().Err(err).Msg("failed to read database settings from environment")
+	}
+
+	// create a config instance with database settings prepopulated
+	cfg := config{
+		DB: dbConfig,
+	}
+
+	// read command line flags used for settings into the config instance
+	port, _ := internal.GetString("port")
+	env, _ := internal.GetString("environment")
+	outboxFile, _ := internal.GetString("outbox_file")
+	adminPort, _ := internal.GetString("admin_port")
+	tracingExporter, _ := internal.GetString("tracing_exporter")
+	tracingFile, _ := internal.GetString("tracing_file")
+	rateLimitStore, _ := internal.GetString("ratelimit_store")
+	rateLimitDisabled, _ := internal.GetString("ratelimit_disabled")
+	corsOrigins, _ := internal.GetString("cors_origins")
+	corsMethods, _ := internal.GetString("cors_methods")
+	corsHeaders, _ := internal.GetString("cors_headers")
+	corsCredentials, _ := internal.GetString("cors_credentials")
+	corsMaxAge, _ := internal.GetString("cors_max_age")
+	graphQLMaxComplexity, _ := internal.GetString("graphql_max_complexity")
+	graphQLMaxDepth, _ := internal.GetString("graphql_max_depth")
+	grpcPort, _ := internal.GetString("grpc_port")
+	batchMaxBodySize, _ := internal.GetString("batch_max_body_size")
+	authSecret, _ := internal.GetString("auth_secret")
+	shutdownDrain, _ := internal.GetString("shutdown_drain")
+
+	if corsMethods == "" {
+		corsMethods = "GET,POST,PUT,PATCH,DELETE"
+	}
+
+	if corsHeaders == "" {
+		corsHeaders = "Authorization,Content-Type,X-Request-Id,Idempotency-Key,Last-Event-ID,traceparent,tracestate"
+	}
+
+	corsMaxAgeDefault, err := time.ParseDuration(corsMaxAge)
 	if err != nil {
-		internal.NewLogger().Log.Panic().Err(err).Msg("failed to get DSN from environment")
+		corsMaxAgeDefault = 10 * time.Minute
 	}
 
-	maxCon, err := internal.GetEnvInt("db_max_cons")
+	graphQLMaxComplexityDefault, err := strconv.Atoi(graphQLMaxComplexity)
 	if err != nil {
-		internal.NewLogger().Log.Panic().Err(err).Msg("failed to get db_max_cons from environment")
+		graphQLMaxComplexityDefault = 1000
 	}
 
-	maxIdleCon, err := internal.GetEnvInt("db_max_idle_cons")
+	graphQLMaxDepthDefault, err := strconv.Atoi(graphQLMaxDepth)
 	if err != nil {
-		internal.NewLogger().Log.Panic().Err(err).Msg("failed to get db_max_idle_cons from environment")
+		graphQLMaxDepthDefault = 10
 	}
 
-	maxIdleTime, err := internal.GetString("db_max_idle_time")
+	batchMaxBodySizeDefault, err := strconv.ParseInt(batchMaxBodySize, 10, 64)
 	if err != nil {
-		internal.NewLogger().Log.Panic().Err(err).Msg("failed to get db_max_idle_time from environment")
+		batchMaxBodySizeDefault = 10 << 20
 	}
 
-	// create a config instance with database settings prepopulated
-	cfg := config{
-		DB: repository.DB_Config{
-			DSN:            dsn,
-			MaxCon:         maxCon,
-			MaxIdleCon:     maxIdleCon,
-			MaxIdleConTime: maxIdleTime,
-		},
+	shutdownDrainDefault, err := time.ParseDuration(shutdownDrain)
+	if err != nil {
+		shutdownDrainDefault = 5 * time.Second
 	}
 
-	// read command line flags used for settings into the config instance
-	port, _ := internal.GetString("port")
-	env, _ := internal.GetString("environment")
+	if rateLimitStore == "" {
+		rateLimitStore = "memory"
+	}
+
+	cfg.AuthSecret = auth.Secret(authSecret)
+
+	cfg.CORS.Origins = splitList(corsOrigins)
+	cfg.CORS.Methods = splitList(corsMethods)
+	cfg.CORS.Headers = splitList(corsHeaders)
+
+	cfg.RateLimit.Policies = map[string]ratelimit.Policy{}
+	for group, spec := range defaultRateLimits {
+		if value, err := internal.GetString("ratelimit_" + group); err == nil {
+			spec = value
+		}
+
+		policy, err := ratelimit.ParsePolicy(spec)
+		if err != nil {
+			internal.NewLogger().Log.Panic().Err(err).Msgf("failed to parse ratelimit_%s from environment", group)
+		}
+		cfg.RateLimit.Policies[group] = policy
+	}
 
 	flag.StringVar(
 		&cfg.Addr,
@@ -68,16 +196,160 @@ func main() {
 		port,
 		"Network port address",
 	)
+	flag.StringVar(
+		&cfg.AdminAddr,
+		"admin-addr",
+		adminPort,
+		"Network port address of the admin server exposing /metrics. Disabled when empty",
+	)
 	flag.StringVar(
 		&cfg.Env,
 		"env",
 		env,
 		"Running Environment. development|staging|production",
 	)
+	flag.Func(
+		"auth-secret",
+		"Key the bearer tokens of users are signed with, at least 32 bytes. Every token is rejected when empty",
+		func(value string) error {
+			cfg.AuthSecret = auth.Secret(value)
+			return nil
+		},
+	)
+	flag.StringVar(
+		&cfg.OutboxFile,
+		"outbox-file",
+		outboxFile,
+		"File published outbox messages are appended to. Disabled when empty",
+	)
+	flag.StringVar(
+		&cfg.Tracing.Exporter,
+		"tracing-exporter",
+		tracingExporter,
+		"Span exporter. none|stdout|file",
+	)
+	flag.StringVar(
+		&cfg.Tracing.File,
+		"tracing-file",
+		tracingFile,
+		"File the file exporter appends spans to as OTLP/JSON lines",
+	)
+	flag.BoolVar(
+		&cfg.RateLimit.Enabled,
+		"ratelimit",
+		rateLimitDisabled != "true",
+		"Rate limit requests per client",
+	)
+	flag.StringVar(
+		&cfg.RateLimit.Store,
+		"ratelimit-store",
+		rateLimitStore,
+		"Where rate limit buckets are kept. memory|postgres",
+	)
+	flag.Func(
+		"cors-origins",
+		"Comma separated origins trusted for cross-origin requests and WebSocket connections, or * for any. Disabled when empty",
+		func(value string) error {
+			cfg.CORS.Origins = splitList(value)
+			return nil
+		},
+	)
+	flag.Func(
+		"cors-methods",
+		"Comma separated methods allowed in cross-origin requests",
+		func(value string) error {
+			cfg.CORS.Methods = splitList(value)
+			return nil
+		},
+	)
+	flag.Func(
+		"cors-headers",
+		"Comma separated request headers allowed in cross-origin requests",
+		func(value string) error {
+			cfg.CORS.Headers = splitList(value)
+			return nil
+		},
+	)
+	flag.BoolVar(
+		&cfg.CORS.Credentials,
+		"cors-credentials",
+		corsCredentials == "true",
+		"Allow cross-origin requests with credentials. Requires cors-origins to list the trusted origins instead of *",
+	)
+	flag.DurationVar(
+		&cfg.CORS.MaxAge,
+		"cors-max-age",
+		corsMaxAgeDefault,
+		"How long browsers may cache preflight responses",
+	)
+	flag.Int64Var(
+		&cfg.BatchMaxBodySize,
+		"batch-max-body-size",
+		batchMaxBodySizeDefault,
+		"Largest body of a batch request in bytes",
+	)
+	flag.DurationVar(
+		&cfg.ShutdownDrain,
+		"shutdown-drain",
+		shutdownDrainDefault,
+		"How long to keep serving after failing readiness on shutdown, so load balancers stop routing new requests first",
+	)
+	flag.StringVar(
+		&cfg.GRPC.Addr,
+		"grpc-addr",
+		grpcPort,
+		"Network port address of the gRPC server, which requires auth-secret. Disabled when empty",
+	)
+	flag.IntVar(
+		&cfg.GraphQL.MaxComplexity,
+		"graphql-max-complexity",
+		graphQLMaxComplexityDefault,
+		"Highest cost of a GraphQL query, counting each field once per item of the lists it is in",
+	)
+	flag.IntVar(
+		&cfg.GraphQL.MaxDepth,
+		"graphql-max-depth",
+		graphQLMaxDepthDefault,
+		"Deepest nesting of fields in a GraphQL query",
+	)
+	for group := range defaultRateLimits {
+		flag.Func(
+			"ratelimit-"+group,
+			"Limits of the "+group+" routes as anon=RATE:BURST,user=RATE:BURST (tokens per second and bucket size)",
+			func(spec string) error {
+				policy, err := ratelimit.ParsePolicy(spec)
+				cfg.RateLimit.Policies[group] = policy
+				return err
+			},
+		)
+	}
 	flag.Parse()
 
+	cfg.Tracing.ServiceName = "fairshare-api"
+
+	if len(cfg.AuthSecret) > 0 && len(cfg.AuthSecret) < auth.MinSecretSize {
+		internal.NewLogger().Log.Panic().Err(auth.ErrShortSecret).Msg("invalid auth_secret")
+	}
+
+	// credentials would be sent to any site echoed back by the * origin
+	if cfg.CORS.Credentials && slices.Contains(cfg.CORS.Origins, "*") {
+		internal.NewLogger().Log.Panic().Msg("cors_credentials cannot be enabled with the * origin, list the trusted origins in cors_origins")
+	}
+
+	// every gRPC call must be authenticated, which no call could be without a secret
+	if cfg.GRPC.Addr != "" && len(cfg.AuthSecret) == 0 {
+		internal.NewLogger().Log.Panic().Msg("grpc_port requires auth_secret to authenticate calls")
+	}
+
 	fmt.Println(cfg)
 
+	// install the tracer provider before anything creates spans
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		internal.NewLogger().Log.Panic().Err(err).Msg("failed to set up tracing")
+	}
+	defer shutdownTracing(context.Background())
+
 	// create a database connection
 	db, err := repository.New(cfg.DB)
 	if err != nil {
@@ -86,24 +358,75 @@ func main() {
 
 	// create application instance and inject config
 	app := application{
-		Config: cfg,
-		Models: repository.NewModels(db),
+		Config:  cfg,
+		Models:  repository.NewModels(db),
+		Hub:     events.NewHub(),
+		Metrics: metrics.New(db),
+		started: time.Now(),
 	}
 
-	// create a server instance
-	server := &http.Server{
-		Addr:    app.Config.Addr,
-		Handler: app.Router(),
+	// keep rate limit buckets in memory, or in Postgres to share them between instances
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Store {
+		case "memory":
+			app.Limiter = ratelimit.NewMemoryStore()
+		case "postgres":
+			app.Limiter = ratelimit.NewPostgresStore(app.Models.RateLimits)
+		default:
+			internal.NewLogger().Log.Panic().Str("store", cfg.RateLimit.Store).Msg("unknown rate limit store")
+		}
+		app.background(app.Limiter.Run)
 	}
 
+	// forward committed group changes from every instance to the hub
+	listener, err := events.NewListener(cfg.DB.DSN, app.Hub)
+	if err != nil {
+		internal.NewLogger().Log.Panic().Err(err).Msg("failed to listen for group events")
+	}
+	defer listener.Close()
+	go listener.Run()
+
+	// publish committed outbox messages to the in-process bus, webhooks and optionally a file
+	bus := outbox.NewBus()
+	bus.Subscribe(func(ctx context.Context, msg *model.OutboxMessage) {
+		app.Hub.Publish(msg.AggregateId)
+	})
+
+	sinks := []outbox.Sink{bus, outbox.WebhookSink{Webhooks: app.Models.Webhooks}}
+	if cfg.OutboxFile != "" {
+		fileSink, err := outbox.NewFileSink(cfg.OutboxFile)
+		if err != nil {
+			internal.NewLogger().Log.Panic().Err(err).Msg("failed to open outbox file")
+		}
+		defer fileSink.Close()
+		sinks = append(sinks, fileSink)
+	}
+
+	app.background(outbox.NewDispatcher(app.Models.Outbox, sinks...).Run)
+
+	// deliver queued webhook events
+	app.background(webhooks.NewDispatcher(app.Models.Webhooks).Run)
+
 	// Run server
 	fmt.Printf(
 		"Server running on port %s in %s environment\n",
 		app.Config.Addr,
 		app.Config.Env,
 	)
-	internal.NewLogger().Log.Info().Str("address", app.Config.Addr).Str("environment", app.Config.Env).Msg("starting server")
-	if err := server.ListenAndServe(); err != nil {
+	if err := app.serve(); err != nil {
 		internal.NewLogger().Log.Panic().Err(err).Msg("failed to start server")
 	}
 }
+
+// splitList splits a comma separated setting into its trimmed, non-empty items.
+func splitList(value string) []string {
+	items := []string{}
+
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
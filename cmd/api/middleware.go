@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/auth"
+	"github.com/Abdul4code/FairShare/internal/compress"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/tracing"
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRequestIDLength is the length of the request_id column of audit_events.
+const maxRequestIDLength = 64
+
+// requestIDOrNew returns id when it is a usable request id, that is at most
+// maxRequestIDLength printable ASCII characters, and a random one otherwise.
+// Client supplied ids end up in logs and audit events, so anything else is
+// replaced rather than trusted.
+func requestIDOrNew(id string) (string, error) {
+	if id != "" && len(id) <= maxRequestIDLength && !strings.ContainsFunc(id, func(r rune) bool {
+		return r < 0x20 || r > 0x7e
+	}) {
+		return id, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// requestID makes sure every request carries an id. A valid client supplied
+// X-Request-Id header is reused, otherwise a random one is generated. The id is
+// stored on the request context and echoed back in the response headers.
+func (app *application) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := requestIDOrNew(r.Header.Get("X-Request-Id"))
+		if err != nil {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, internal.ContextSetRequestID(r, id))
+	})
+}
+
+// localize selects the language of response messages from the Accept-Language
+// header, stores it on the request context and announces it in Content-Language.
+func (app *application) localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Match(r.Header.Get("Accept-Language"))
+
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", lang)
+
+		next.ServeHTTP(w, r.WithContext(i18n.WithLanguage(r.Context(), lang)))
+	})
+}
+
+// authenticate verifies the bearer token in the Authorization header and
+// stores the id of the user it was issued to, and whether the user is an
+// administrator, on the request context. WebSocket handshakes and event
+// streams may pass the token in the access_token query parameter instead, as
+// browsers cannot set their headers. Requests without a token are treated as
+// anonymous; an invalid or expired token is rejected.
+func (app *application) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok && (websocket.IsWebSocketUpgrade(r) || acceptsEventStream(r)) {
+			token = r.URL.Query().Get("access_token")
+			ok = token != ""
+		}
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := auth.Verify(app.Config.AuthSecret, token, time.Now())
+		if err != nil {
+			internal.InvalidTokenError(w, r, err)
+			return
+		}
+
+		// Verify only accepts claims identifying a user
+		actor, _ := claims.UserId()
+		r = internal.ContextSetActor(r, actor)
+		if claims.Admin {
+			r = internal.ContextSetAdmin(r)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerToken returns the token of an Authorization header using the Bearer
+// scheme, and whether there is one.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// acceptsEventStream reports whether r asks for Server-Sent Events, as the
+// EventSource of browsers does.
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// requireUser restricts next to authenticated users, rejecting anonymous
+// requests with 401 Unauthorized.
+func (app *application) requireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := internal.ContextGetActor(r.Context()); !ok {
+			internal.UnauthorizedError(w, r, nil)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// requireAdmin restricts next to administrators: anonymous requests are
+// rejected with 401 Unauthorized and other users with 403 Forbidden.
+func (app *application) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return app.requireUser(func(w http.ResponseWriter, r *http.Request) {
+		if !internal.ContextIsAdmin(r.Context()) {
+			internal.ForbiddenError(w, r)
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// requireGroupMember restricts next, a route of the group identified by the id
+// URL parameter, to the members of the group and administrators: anonymous
+// requests are rejected with 401 Unauthorized and other users with 403
+// Forbidden.
+func (app *application) requireGroupMember(next http.HandlerFunc) http.HandlerFunc {
+	return app.requireUser(func(w http.ResponseWriter, r *http.Request) {
+		id, err := internal.ReadParamId(r)
+		if err != nil {
+			internal.NotFoundError(w, r)
+			return
+		}
+
+		member, err := app.canReadGroup(r.Context(), id)
+		if err != nil {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+		if !member {
+			internal.ForbiddenError(w, r)
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// canReadGroup reports whether the user of ctx may read the history and
+// activity of the group identified by groupId: administrators may read every
+// group, and other users the groups they are a member of.
+func (app *application) canReadGroup(ctx context.Context, groupId int) (bool, error) {
+	if internal.ContextIsAdmin(ctx) {
+		return true, nil
+	}
+
+	userId, ok := internal.ContextGetActor(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	return app.Models.Members.IsMember(ctx, groupId, userId)
+}
+
+// metrics records the count and latency of every request, labelled by the
+// pattern of the route that served it (e.g. /v1/groups/:id) and its status.
+func (app *application) metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		app.Metrics.RequestStarted()
+
+		r = internal.ContextSetRoute(r)
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			app.Metrics.RequestFinished(r.Method, routePattern(r), sw.status, time.Since(start))
+		}()
+
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// trace starts a server span for every request, continuing the caller's trace
+// when the request carries a W3C traceparent header. Once the request is
+// served, the span is named after the pattern of the route that served it, and
+// the trace context is returned to the client in the traceparent response
+// header.
+func (app *application) trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Tracer().Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("request.id", internal.ContextGetRequestID(r.Context())),
+			),
+		)
+		defer span.End()
+
+		propagator.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+		r = internal.ContextSetRoute(r.WithContext(ctx))
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+
+		route := routePattern(r)
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", sw.status),
+		)
+		if sw.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sw.status))
+		}
+	})
+}
+
+// compress compresses response bodies with the brotli or gzip content coding
+// when the client accepts one of them.
+func (app *application) compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := compress.Negotiate(r)
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := compress.NewWriter(w, encoding)
+		defer func() {
+			if err := cw.Close(); err != nil {
+				internal.NewLogger().Log.Error().Err(err).Msg("failed to finish compressed response")
+			}
+		}()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// headers of API responses that browsers expose to cross-origin callers
+var corsExposedHeaders = []string{
+	"X-Request-Id",
+	"Retry-After",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"traceparent",
+}
+
+// cors allows browsers on trusted origins to read API responses. Requests from
+// an origin in the configuration get the origin echoed back in
+// Access-Control-Allow-Origin; requests from other origins are served without
+// CORS headers, so the browser blocks the response. Preflight requests are
+// answered by preflight.
+func (app *application) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin != "" && app.Config.CORS.allowsOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+
+			// credentials are only shared with origins listed by name, never through *
+			if app.Config.CORS.Credentials && slices.Contains(app.Config.CORS.Origins, origin) {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers OPTIONS requests to every registered route. The router
+// has already set the Allow header to the methods of the route; a CORS
+// preflight from a trusted origin asking for one of those methods is answered
+// with the allowed methods, headers and max-age.
+func (app *application) preflight(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	origin := r.Header.Get("Origin")
+
+	if method != "" && origin != "" && app.Config.CORS.allowsOrigin(origin) {
+		allowed := []string{}
+		for m := range strings.SplitSeq(w.Header().Get("Allow"), ",") {
+			m = strings.TrimSpace(m)
+			if slices.Contains(app.Config.CORS.Methods, m) {
+				allowed = append(allowed, m)
+			}
+		}
+
+		if slices.Contains(allowed, method) {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(app.Config.CORS.Headers, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(app.Config.CORS.MaxAge.Seconds())))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowsOrigin reports whether cross-origin requests from origin are trusted.
+func (c corsConfig) allowsOrigin(origin string) bool {
+	return slices.Contains(c.Origins, "*") || slices.Contains(c.Origins, origin)
+}
+
+// rate limit route groups, each configured with its own policy
+const (
+	rateLimitRead     = "read"     // reads of groups, feeds and history
+	rateLimitWrite    = "write"    // changes to groups and webhooks
+	rateLimitRealtime = "realtime" // opening event streams and WebSocket connections
+)
+
+// maxBodySize lets the handler read request bodies of up to size bytes with
+// internal.ReadJSON, instead of internal.DefaultMaxBodySize.
+func (app *application) maxBodySize(size int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, internal.ContextSetMaxBodySize(r, size))
+	}
+}
+
+// rateLimit limits the requests a client makes to the routes of a group with a
+// token bucket per client: users authenticated by a verified bearer token are
+// limited by user id, other clients by IP address. Request headers naming a
+// user, such as X-User-Id, are ignored, so they cannot be used to get a fresh
+// bucket. Limited responses carry the RateLimit-*
+// headers, and requests over the limit are rejected with 429 and Retry-After.
+// The request is let through when the store fails, so an unavailable store
+// does not take the API down.
+func (app *application) rateLimit(group string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if app.Limiter == nil {
+			next(w, r)
+			return
+		}
+
+		policy := app.Config.RateLimit.Policies[group]
+
+		limit, key := policy.Anonymous, group+":ip:"+clientIP(r)
+		if actor, ok := internal.ContextGetActor(r.Context()); ok {
+			limit, key = policy.User, group+":user:"+strconv.Itoa(actor)
+		}
+
+		if !limit.Enabled() {
+			next(w, r)
+			return
+		}
+
+		result, err := app.Limiter.Take(r.Context(), key, limit)
+		if err != nil {
+			internal.NewLogger().Log.Error().Err(err).Str("key", key).Msg("failed to check rate limit")
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Window())))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+			internal.RateLimitExceededError(w, r)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// clientIP returns the IP address of the client that opened the connection.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds returns d in whole seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// routePattern returns the pattern of the route that served the request, as
+// recorded by the route when it was registered, or "unmatched" when no route
+// served it. Custom methods are reported by their path.
+func routePattern(r *http.Request) string {
+	if route := internal.ContextGetRoute(r.Context()); route != "" {
+		return route
+	}
+	return "unmatched"
+}
+
+// statusWriter wraps an http.ResponseWriter to remember the status code written.
+// It keeps the streaming and hijacking abilities of the wrapped writer, which
+// event streams and WebSocket upgrades depend on.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code and writes it to the wrapped writer.
+func (sw *statusWriter) WriteHeader(status int) {
+	if !sw.wroteHeader {
+		sw.status = status
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes to the wrapped writer, recording an implicit 200 status.
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher when the wrapped writer does.
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the wrapped writer does. A hijacked
+// connection is reported with status 101 Switching Protocols.
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer does not support hijacking")
+	}
+
+	sw.status = http.StatusSwitchingProtocols
+	sw.wroteHeader = true
+	return h.Hijack()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
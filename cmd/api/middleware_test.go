@@ -0,0 +1,208 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/auth"
+	"github.com/Abdul4code/FairShare/internal/ratelimit"
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var testSecret = auth.Secret("0123456789abcdef0123456789abcdef")
+
+// testToken returns a token for the user signed with testSecret.
+func testToken(t *testing.T, user string, admin bool) string {
+	t.Helper()
+
+	token, err := auth.Sign(testSecret, auth.Claims{Subject: user, Admin: admin, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRateLimitKeysOnAuthenticatedUser(t *testing.T) {
+	policy, err := ratelimit.ParsePolicy("anon=0.001:1,user=0.001:3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &application{Limiter: ratelimit.NewMemoryStore()}
+	app.Config.AuthSecret = testSecret
+	app.Config.RateLimit.Policies = map[string]ratelimit.Policy{rateLimitRead: policy}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	h := app.authenticate(app.rateLimit(rateLimitRead, ok))
+
+	send := func(header string, value string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/groups", nil)
+		r.RemoteAddr = "203.0.113.7:5000"
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := send("", ""); w.Code != http.StatusOK {
+		t.Fatalf("first anonymous request: status %d, want 200", w.Code)
+	}
+
+	// a spoofed user id shares the bucket of the IP address
+	for _, id := range []string{"1", "2", "3"} {
+		if w := send("X-User-Id", id); w.Code != http.StatusTooManyRequests {
+			t.Errorf("X-User-Id %s: status %d, want 429", id, w.Code)
+		}
+	}
+
+	// a verified user gets a bucket of its own, with the user limit
+	w := send("Authorization", "Bearer "+testToken(t, "42", false))
+	if w.Code != http.StatusOK {
+		t.Fatalf("authenticated request: status %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("RateLimit-Limit"); got != "3" {
+		t.Errorf("RateLimit-Limit = %s, want 3", got)
+	}
+
+	if w := send("Authorization", "Bearer forged.token.value"); w.Code != http.StatusUnauthorized {
+		t.Errorf("forged token: status %d, want 401", w.Code)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"client id", "req-1", true},
+		{"longest id", strings.Repeat("a", maxRequestIDLength), true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"control character", "req\x001", false},
+		{"not ASCII", "req-é", false},
+	}
+
+	handler := newTestApp().requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(internal.ContextGetRequestID(r.Context())))
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/groups", nil)
+			r.Header.Set("X-Request-Id", tt.header)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			id := w.Header().Get("X-Request-Id")
+			if id != w.Body.String() {
+				t.Errorf("header %q differs from context %q", id, w.Body.String())
+			}
+			if tt.keep && id != tt.header {
+				t.Errorf("id = %q, want %q", id, tt.header)
+			}
+			if !tt.keep && (id == tt.header || len(id) != 32) {
+				t.Errorf("id = %q, want a generated id", id)
+			}
+		})
+	}
+}
+
+func TestRoutePattern(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	app := newTestApp()
+	app.Models = repository.NewModels(db)
+	routes, _ := app.routes()
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/v1/groups", "/v1/groups"},
+		{http.MethodGet, "/v1/groups/3", "/v1/groups/:id"},
+		{http.MethodGet, "/v1/groups/v1", "/v1/groups/:id"},
+		{http.MethodGet, "/v1/groups/3/history", "/v1/groups/:id/history"},
+		{http.MethodPost, "/v1/webhooks/3/deliveries/3/redeliver", "/v1/webhooks/:id/deliveries/:delivery_id/redeliver"},
+		{http.MethodPost, "/v1/groups:batch", "/v1/groups:batch"},
+		{http.MethodGet, "/v1/docs/", "/v1/docs/*filepath"},
+		{http.MethodGet, "/v1/docs/assets/v1/docs/app.js", "/v1/docs/*filepath"},
+		{http.MethodGet, "/v1/nothing/here", "unmatched"},
+		{http.MethodPut, "/v1/groups", "unmatched"},
+		{http.MethodGet, "/v1/groups:batch", "unmatched"},
+	}
+
+	for _, tt := range tests {
+		r := internal.ContextSetRoute(httptest.NewRequest(tt.method, tt.path, nil))
+		routes.ServeHTTP(httptest.NewRecorder(), r)
+
+		if got := routePattern(r); got != tt.want {
+			t.Errorf("routePattern(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRequireGroupMember(t *testing.T) {
+	auditColumns := []string{"count", "id", "actor_id", "action", "entity_type", "entity_id", "request_id", "before", "after", "created_at"}
+
+	tests := []struct {
+		name   string
+		token  string
+		expect func(mock sqlmock.Sqlmock)
+		want   int
+	}{
+		{"anonymous", "", func(mock sqlmock.Sqlmock) {}, http.StatusUnauthorized},
+		{"non-member", testToken(t, "2", false), func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT EXISTS`).WithArgs(3, 2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		}, http.StatusForbidden},
+		{"member", testToken(t, "1", false), func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`SELECT EXISTS`).WithArgs(3, 1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			mock.ExpectQuery(`FROM audit_events`).WillReturnRows(sqlmock.NewRows(auditColumns))
+		}, http.StatusOK},
+		{"administrator", testToken(t, "2", true), func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery(`FROM audit_events`).WillReturnRows(sqlmock.NewRows(auditColumns))
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			app := newTestApp()
+			app.Models = repository.NewModels(db)
+			tt.expect(mock)
+
+			r := httptest.NewRequest(http.MethodGet, "/v1/groups/3/history", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			app.Router().ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status %d, want %d", w.Code, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
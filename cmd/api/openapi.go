@@ -0,0 +1,527 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/openapi"
+	"github.com/julienschmidt/httprouter"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// routeDoc documents a route of the API in the OpenAPI document.
+type routeDoc struct {
+	Operation   string   // unique operationId, e.g. listGroups
+	Summary     string   // one line description of the operation
+	Description string   // optional longer description
+	Tag         string   // group of operations in the documentation
+	Query       any      // struct whose fields are the query parameters
+	QueryFields []string // JSON names of the Query fields read by the route, all when empty
+	Body        any      // request body
+	Response    any      // body of the success response
+	Status      int      // status of the success response, 200 when zero
+	ContentType string   // content type of the success response, application/json when empty
+	Alternates  []string // further content types of the success response, chosen by Accept
+	Statuses    []int    // further statuses returning the success response body
+	Errors      []int    // error statuses besides 400, 404, 429 and 500, which are derived
+	Unlimited   bool     // the route is not rate limited
+}
+
+// response bodies without a model of their own
+type (
+	messageResponse = struct {
+		Message string `json:"message"`
+	}
+	groupPage = struct {
+		Metadata model.MetaData `json:"metadata"`
+		Data     []model.Group  `json:"data"`
+	}
+	searchPage = struct {
+		Metadata model.MetaData       `json:"metadata"`
+		Data     []model.SearchResult `json:"data"`
+	}
+	auditPage = struct {
+		Metadata model.MetaData     `json:"metadata"`
+		Data     []model.AuditEvent `json:"data"`
+	}
+	activityPage = struct {
+		Metadata model.ActivityMetaData `json:"metadata"`
+		Data     []model.Activity       `json:"data"`
+	}
+	webhookPage = struct {
+		Metadata model.MetaData  `json:"metadata"`
+		Data     []model.Webhook `json:"data"`
+	}
+	deliveryPage = struct {
+		Metadata model.MetaData          `json:"metadata"`
+		Data     []model.WebhookDelivery `json:"data"`
+	}
+	healthResponse = struct {
+		Status      string `json:"status"`
+		Environment string `json:"environment"`
+	}
+	liveResponse = struct {
+		Status string            `json:"status"`
+		Build  map[string]string `json:"build"`
+		Uptime string            `json:"uptime"`
+	}
+	readyResponse = struct {
+		Status      string                     `json:"status" validate:"oneof=ready unavailable shutting_down"`
+		Environment string                     `json:"environment"`
+		Components  map[string]componentStatus `json:"components"`
+		Build       map[string]string          `json:"build"`
+		Uptime      string                     `json:"uptime"`
+	}
+	eventsQuery = struct {
+		LastEventId int64  `json:"last_event_id" validate:"min=0"`
+		AccessToken string `json:"access_token"`
+	}
+	wsQuery = struct {
+		AccessToken string `json:"access_token"`
+	}
+)
+
+// apiDocs documents every route of the API, keyed by method and path as
+// registered on the router. Router logs the routes missing from it, and
+// TestRoutesAreDocumented fails on them.
+var apiDocs = map[string]routeDoc{
+	"GET /v1/health": {
+		Operation: "getHealth", Tag: "health", Unlimited: true,
+		Summary:  "Report that the API is available",
+		Response: healthResponse{},
+	},
+	"GET /v1/health/live": {
+		Operation: "getLiveness", Tag: "health", Unlimited: true,
+		Summary:  "Liveness probe: report that the process is up",
+		Response: liveResponse{},
+	},
+	"GET /v1/health/ready": {
+		Operation: "getReadiness", Tag: "health", Unlimited: true,
+		Summary:     "Readiness probe: check the database and schema version",
+		Description: "Responds 503 with the same body when a dependency is down or the server is shutting down.",
+		Response:    readyResponse{},
+		Statuses:    []int{http.StatusServiceUnavailable},
+	},
+	"GET /v1/graphql": {
+		Operation: "queryGraphQL", Tag: "graphql",
+		Summary:     "Run a GraphQL query",
+		Description: "Runs a query, but not a mutation, of the GraphQL schema. Errors of the query are returned in the errors of a 200 response, with the code and status of the matching REST error in their extensions.",
+		Query:       graphQLQuery{},
+		Response:    graphQLResponse{},
+		Errors:      []int{http.StatusMethodNotAllowed},
+	},
+	"POST /v1/graphql": {
+		Operation: "executeGraphQL", Tag: "graphql",
+		Summary:     "Run a GraphQL query or mutation",
+		Description: "Errors of the operation are returned in the errors of a 200 response, with the code and status of the matching REST error in their extensions.",
+		Body:        graphQLRequest{},
+		Response:    graphQLResponse{},
+	},
+	"GET /v1/openapi.json": {
+		Operation: "getOpenAPI", Tag: "docs", Unlimited: true,
+		Summary:  "This OpenAPI document",
+		Response: map[string]any{},
+	},
+	"GET /v1/docs/*filepath": {
+		Operation: "getDocs", Tag: "docs", Unlimited: true,
+		Summary:     "Interactive documentation of the API",
+		ContentType: "text/html",
+	},
+	"POST /v1/groups": {
+		Operation: "createGroup", Tag: "groups",
+		Summary:  "Create a group",
+		Body:     model.GroupInput{},
+		Response: model.Group{},
+		Status:   http.StatusCreated,
+	},
+	"POST /v1/groups:batch": {
+		Operation: "batchGroups", Tag: "groups",
+		Summary:     "Create, replace and delete groups in a batch",
+		Description: "Each operation is validated like the request it stands for and reported with the status of that request. An atomic batch applies every operation or none: when one fails, the response has its status and the other operations are reported as 424. A best-effort batch applies each operation on its own.",
+		Body:        model.GroupBatch{},
+		Response:    model.GroupBatchResponse{},
+		Statuses:    []int{http.StatusConflict},
+	},
+	"GET /v1/groups": {
+		Operation: "listGroups", Tag: "groups",
+		Summary:     "List groups",
+		Description: "Returns a page of groups as JSON, or streams every matching group as CSV or NDJSON, ignoring pagination, when requested through the Accept header.\n\nThe filter parameter takes an expression over id, name, currency, description, created_by, created_at and version, such as `currency in (Euro, Pound) and (created_at >= 2025-01-01 or version > 1)`. Comparisons use =, !=, <, <=, >, >=, ~ (contains, ignoring case) or in with a list of values, and are combined with and, or, not and parentheses. Values with spaces are quoted with ' or \".\n\nThe sort parameter takes a comma separated list of fields, each prefixed with - for descending order, such as `currency,-created_at`. Groups are finally sorted by id.",
+		Query:       model.GroupQuery{},
+		Response:    groupPage{},
+		Alternates:  []string{contentTypeCSV, contentTypeNDJSON},
+		Errors:      []int{http.StatusNotAcceptable},
+	},
+	"GET /v1/search": {
+		Operation: "searchGroups", Tag: "groups",
+		Summary:     "Search groups by the words of their name and description",
+		Description: "Returns a page of the groups matching q, most relevant first. Words are stemmed in the language of the Accept-Language header, and misspelled group names are matched by similarity. q accepts \"quoted phrases\", or between alternatives and - before excluded words. The highlight of each result is HTML: the text of the group is escaped and the matching words are wrapped in <mark> and </mark>.",
+		Query:       model.SearchQuery{},
+		Response:    searchPage{},
+	},
+	"GET /v1/groups/:id": {
+		Operation: "getGroup", Tag: "groups",
+		Summary:  "Get a group",
+		Response: model.Group{},
+	},
+	"PUT /v1/groups/:id": {
+		Operation: "replaceGroup", Tag: "groups",
+		Summary:  "Replace a group",
+		Body:     model.GroupInput{},
+		Response: model.Group{},
+		Errors:   []int{http.StatusConflict},
+	},
+	"PATCH /v1/groups/:id": {
+		Operation: "updateGroup", Tag: "groups",
+		Summary:  "Update some fields of a group",
+		Body:     model.GroupUpdate{},
+		Response: model.Group{},
+		Errors:   []int{http.StatusConflict},
+	},
+	"DELETE /v1/groups/:id": {
+		Operation: "deleteGroup", Tag: "groups",
+		Summary:  "Delete a group",
+		Response: messageResponse{},
+	},
+	"GET /v1/groups/:id/history": {
+		Operation: "listGroupHistory", Tag: "audit",
+		Summary:     "List the audit events of a group",
+		Description: "Restricted to the members of the group and administrators.",
+		Query:       model.AuditQuery{},
+		QueryFields: []string{"from", "to", "page", "page_size"},
+		Response:    auditPage{},
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+	"GET /v1/groups/:id/activity": {
+		Operation: "listGroupActivity", Tag: "activity",
+		Summary:     "List the activity feed of a group, newest first",
+		Description: "Restricted to the members of the group and administrators.",
+		Query:       model.ActivityQuery{},
+		QueryFields: []string{"cursor", "limit"},
+		Response:    activityPage{},
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+	"POST /v1/groups/:id/activity/read": {
+		Operation: "markGroupActivityRead", Tag: "activity",
+		Summary: "Mark the activity feed of a group as read",
+		Body:    model.ActivityRead{},
+		Response: struct {
+			LastReadId int64 `json:"last_read_id"`
+		}{},
+		Errors: []int{http.StatusUnauthorized},
+	},
+	"GET /v1/groups/:id/events": {
+		Operation: "streamGroupEvents", Tag: "activity",
+		Summary:     "Stream the activity of a group as Server-Sent Events",
+		Description: "Resumes after the activity given by the Last-Event-ID header or the last_event_id query parameter. Restricted to the members of the group and administrators, authenticated by the bearer token in the Authorization header or, for EventSource clients, in the access_token query parameter.",
+		Query:       eventsQuery{},
+		ContentType: "text/event-stream",
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+	"GET /v1/ws": {
+		Operation: "openWebSocket", Tag: "activity",
+		Summary:     "Open a WebSocket to subscribe to the activity of groups",
+		Description: "Requires a user, authenticated by the bearer token in the Authorization header or, for clients that cannot set headers, in the access_token query parameter. Browsers may only connect from the origins trusted for cross-origin requests.",
+		Query:       wsQuery{},
+		Status:      http.StatusSwitchingProtocols,
+		ContentType: "-",
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+	"POST /v1/webhooks": {
+		Operation: "createWebhook", Tag: "webhooks",
+		Summary:     "Create a webhook",
+		Description: "The webhook receives the events of the groups its creator is a member of.",
+		Body:        model.WebhookInput{},
+		Response:    model.Webhook{},
+		Status:      http.StatusCreated,
+		Errors:      []int{http.StatusUnauthorized},
+	},
+	"GET /v1/webhooks": {
+		Operation: "listWebhooks", Tag: "webhooks",
+		Summary:     "List webhooks",
+		Description: "Lists the webhooks of the user, or every webhook for administrators.",
+		Query:       model.WebhookQuery{},
+		QueryFields: []string{"page", "page_size"},
+		Response:    webhookPage{},
+		Errors:      []int{http.StatusUnauthorized},
+	},
+	"GET /v1/webhooks/:id": {
+		Operation: "getWebhook", Tag: "webhooks",
+		Summary:  "Get a webhook",
+		Response: model.Webhook{},
+		Errors:   []int{http.StatusUnauthorized},
+	},
+	"PATCH /v1/webhooks/:id": {
+		Operation: "updateWebhook", Tag: "webhooks",
+		Summary:  "Update some fields of a webhook",
+		Body:     model.WebhookUpdate{},
+		Response: model.Webhook{},
+		Errors:   []int{http.StatusUnauthorized},
+	},
+	"DELETE /v1/webhooks/:id": {
+		Operation: "deleteWebhook", Tag: "webhooks",
+		Summary:  "Delete a webhook",
+		Response: messageResponse{},
+		Errors:   []int{http.StatusUnauthorized},
+	},
+	"GET /v1/webhooks/:id/deliveries": {
+		Operation: "listWebhookDeliveries", Tag: "webhooks",
+		Summary:     "List the deliveries of a webhook",
+		Query:       model.WebhookQuery{},
+		QueryFields: []string{"status", "page", "page_size"},
+		Response:    deliveryPage{},
+		Errors:      []int{http.StatusUnauthorized},
+	},
+	"POST /v1/webhooks/:id/deliveries/:delivery_id/redeliver": {
+		Operation: "redeliverWebhookDelivery", Tag: "webhooks",
+		Summary:  "Queue a delivery to be sent again",
+		Response: model.WebhookDelivery{},
+		Status:   http.StatusAccepted,
+		Errors:   []int{http.StatusUnauthorized},
+	},
+	"GET /v1/audit": {
+		Operation: "listAuditEvents", Tag: "audit",
+		Summary:     "List audit events across all entities",
+		Description: "Restricted to administrators.",
+		Query:       model.AuditQuery{},
+		Response:    auditPage{},
+		Errors:      []int{http.StatusUnauthorized, http.StatusForbidden},
+	},
+}
+
+// describe the custom validation rules of the models in the schemas
+func init() {
+	openapi.DescribeRule("oneof_currency", func(schema *openapi.Schema, param string) {
+		for _, currency := range model.SupportedCurrencies {
+			schema.Enum = append(schema.Enum, currency)
+		}
+	})
+
+	openapi.DescribeRule("oneof_event_type", func(schema *openapi.Schema, param string) {
+		for _, eventType := range model.WebhookEventTypes {
+			schema.Enum = append(schema.Enum, eventType)
+		}
+	})
+
+	openapi.DescribeRule("sort", func(schema *openapi.Schema, param string) {
+		for _, field := range strings.Fields(param) {
+			schema.Enum = append(schema.Enum, field, "-"+field)
+		}
+	})
+}
+
+// apiSpec builds the OpenAPI document of the routes registered on the router.
+type apiSpec struct {
+	gen        *openapi.Generator
+	doc        *openapi.Document
+	registered map[string]bool // documented routes that were added
+	routes     []string        // every route added, by method and path
+	errs       []error         // routes added without documentation
+}
+
+// newAPISpec returns an apiSpec without operations.
+func newAPISpec() *apiSpec {
+	gen := openapi.NewGenerator()
+
+	// every error response shares the shape written by internal.WriteError
+	gen.Schemas()["Error"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"error": {
+				Description: "A message, or a message per invalid field for validation errors",
+				OneOf: []*openapi.Schema{
+					{Type: "string"},
+					{Type: "object", AdditionalProperties: &openapi.Schema{Type: "string"}},
+				},
+			},
+		},
+		Required: []string{"error"},
+	}
+
+	return &apiSpec{
+		gen: gen,
+		doc: &openapi.Document{
+			OpenAPI: openapi.Version,
+			Info: openapi.Info{
+				Title:       "FairShare API",
+				Version:     version,
+				Description: "Expense sharing API. Requests are attributed to the user identified by the bearer token in the Authorization header; requests without a token are anonymous.",
+			},
+			Paths: map[string]*openapi.PathItem{},
+			Components: openapi.Components{
+				Schemas: gen.Schemas(),
+				SecuritySchemes: map[string]*openapi.SecurityScheme{
+					"bearerAuth": {
+						Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+						Description: "HS256 token whose sub claim is the id of the user, and whose admin claim grants the admin routes.",
+					},
+				},
+			},
+			Security: []openapi.SecurityRequirement{{"bearerAuth": {}}, {}},
+		},
+		registered: map[string]bool{},
+	}
+}
+
+// routeParam matches the :name and *name parameters of router paths, which
+// start a path segment; the colon of a custom method such as :batch does not.
+var routeParam = regexp.MustCompile(`/[:*](\w+)`)
+
+// add documents the route registered for method and path from apiDocs. A
+// route without documentation is left out of the document and reported by
+// check.
+func (s *apiSpec) add(method string, path string) {
+	key := method + " " + path
+	s.routes = append(s.routes, key)
+
+	doc, ok := apiDocs[key]
+	if !ok {
+		s.errs = append(s.errs, fmt.Errorf("route %s has no documentation, add it to apiDocs in cmd/api/openapi.go", key))
+		return
+	}
+	s.registered[key] = true
+
+	op := &openapi.Operation{
+		OperationId: doc.Operation,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        []string{doc.Tag},
+		Responses:   map[string]*openapi.Response{},
+	}
+
+	for _, match := range routeParam.FindAllStringSubmatch(path, -1) {
+		schema := &openapi.Schema{Type: "string"}
+		if match[1] == "id" || strings.HasSuffix(match[1], "_id") {
+			minimum := 1.0
+			schema = &openapi.Schema{Type: "integer", Minimum: &minimum}
+		}
+		op.Parameters = append(op.Parameters, openapi.Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+
+	if doc.Query != nil {
+		op.Parameters = append(op.Parameters, s.gen.Parameters("query", doc.Query, doc.QueryFields...)...)
+	}
+
+	if doc.Body != nil {
+		op.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]openapi.MediaType{contentTypeJSON: {Schema: s.gen.Schema(doc.Body)}},
+		}
+	}
+
+	success := &openapi.Response{Description: http.StatusText(orDefault(doc.Status, http.StatusOK))}
+	switch doc.ContentType {
+	case "-":
+	case "", contentTypeJSON:
+		success.Content = map[string]openapi.MediaType{contentTypeJSON: {Schema: s.gen.Schema(doc.Response)}}
+	default:
+		success.Content = map[string]openapi.MediaType{doc.ContentType: {Schema: &openapi.Schema{Type: "string"}}}
+	}
+
+	for _, alternate := range doc.Alternates {
+		schema := &openapi.Schema{Type: "string"}
+		if alternate == contentTypeNDJSON {
+			schema = s.gen.Schema(model.Group{})
+		}
+		success.Content[alternate] = openapi.MediaType{Schema: schema}
+	}
+
+	op.Responses[strconv.Itoa(orDefault(doc.Status, http.StatusOK))] = success
+	for _, status := range doc.Statuses {
+		op.Responses[strconv.Itoa(status)] = &openapi.Response{Description: http.StatusText(status), Content: success.Content}
+	}
+
+	errors := slices.Clone(doc.Errors)
+	if doc.Body != nil || doc.Query != nil {
+		errors = append(errors, http.StatusBadRequest)
+	}
+	if routeParam.MatchString(path) {
+		errors = append(errors, http.StatusNotFound)
+	}
+	if !doc.Unlimited {
+		errors = append(errors, http.StatusTooManyRequests)
+	}
+	errors = append(errors, http.StatusInternalServerError)
+
+	for _, status := range errors {
+		op.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Description: http.StatusText(status),
+			Content: map[string]openapi.MediaType{
+				contentTypeJSON: {Schema: &openapi.Schema{Ref: "#/components/schemas/Error"}},
+			},
+		}
+	}
+
+	openAPIPath := routeParam.ReplaceAllString(path, "/{$1}")
+	item, ok := s.doc.Paths[openAPIPath]
+	if !ok {
+		item = &openapi.PathItem{}
+		s.doc.Paths[openAPIPath] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// check returns an error listing the routes added without documentation and
+// the routes apiDocs documents that were not added.
+func (s *apiSpec) check() error {
+	errs := slices.Clone(s.errs)
+	for _, key := range slices.Sorted(maps.Keys(apiDocs)) {
+		if !s.registered[key] {
+			errs = append(errs, fmt.Errorf("apiDocs documents %s, which is not a registered route", key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// orDefault returns value, or fallback when value is zero.
+func orDefault(value int, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// openAPIHandler returns the handler of GET /v1/openapi.json, serving the
+// document built from spec.
+func (app *application) openAPIHandler(spec *apiSpec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		internal.WriteJSON(w, http.StatusOK, spec.doc)
+	}
+}
+
+// swaggerInitializer configures the embedded Swagger UI to load the API document.
+const swaggerInitializer = `window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: "/v1/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// docsHandler handles GET /v1/docs/*filepath. It serves the embedded Swagger
+// UI, configured to display the document of /v1/openapi.json.
+func (app *application) docsHandler(w http.ResponseWriter, r *http.Request) {
+	file := httprouter.ParamsFromContext(r.Context()).ByName("filepath")
+
+	if file == "/swagger-initializer.js" {
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Write([]byte(swaggerInitializer))
+		return
+	}
+
+	r = r.Clone(r.Context())
+	r.URL.Path = file
+
+	http.FileServer(swaggerFiles.HTTP).ServeHTTP(w, r)
+}
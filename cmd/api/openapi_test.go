@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/events"
+	"github.com/Abdul4code/FairShare/internal/metrics"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// newTestApp returns an application without a database or rate limiter.
+func newTestApp() *application {
+	app := &application{
+		Models:  repository.NewModels(nil),
+		Hub:     events.NewHub(),
+		Metrics: metrics.New(nil),
+		started: time.Now(),
+	}
+	app.Config.AuthSecret = testSecret
+	return app
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	app := newTestApp()
+
+	_, spec := app.routes()
+	if err := spec.check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the document served by the API lists every registered route
+	w := httptest.NewRecorder()
+	app.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /v1/openapi.json: status %d", w.Code)
+	}
+
+	doc := struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range spec.routes {
+		method, path, _ := strings.Cut(key, " ")
+		openAPIPath := routeParam.ReplaceAllString(path, "/{$1}")
+
+		if _, ok := doc.Paths[openAPIPath][strings.ToLower(method)]; !ok {
+			t.Errorf("route %s is missing from the document as %s %s", key, method, openAPIPath)
+		}
+	}
+}
+
+func TestSpecReportsUndocumentedRoutes(t *testing.T) {
+	spec := newAPISpec()
+	spec.add(http.MethodGet, "/v1/undocumented")
+
+	err := spec.check()
+	if err == nil || !strings.Contains(err.Error(), "GET /v1/undocumented has no documentation") {
+		t.Fatalf("check() = %v, want an error naming GET /v1/undocumented", err)
+	}
+}
@@ -1,15 +1,32 @@
 package main
 
 import (
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/Abdul4code/FairShare/internal"
 	"github.com/julienschmidt/httprouter"
 )
 
-// Router constructs and returns the application's HTTP router with routes and custom
-// NotFound and MethodNotAllowed handlers wired up.
-func (app *application) Router() *httprouter.Router {
+// Router constructs and returns the application's HTTP handler: the router with routes
+// and custom NotFound and MethodNotAllowed handlers wired up, wrapped in the middleware chain.
+// Routes missing from the OpenAPI document are logged rather than stopping
+// the server; TestRoutesAreDocumented catches them before they ship.
+func (app *application) Router() http.Handler {
+	routes, spec := app.routes()
+
+	if err := spec.check(); err != nil {
+		internal.NewLogger().Log.Error().Err(err).Msg("the OpenAPI document does not match the routes")
+	}
+
+	return app.requestID(app.localize(app.cors(app.compress(app.trace(app.metrics(app.authenticate(routes)))))))
+}
+
+// routes registers every route of the API and returns them with the OpenAPI
+// document describing them.
+func (app *application) routes() (*routeTable, *apiSpec) {
 	// instantiate new router
 	router := httprouter.New()
 
@@ -17,16 +34,137 @@ func (app *application) Router() *httprouter.Router {
 	router.NotFound = http.HandlerFunc(internal.NotFoundError)
 	router.MethodNotAllowed = http.HandlerFunc(internal.MethodNotAllowed)
 
+	// answer OPTIONS and CORS preflight requests for every route
+	router.GlobalOPTIONS = http.HandlerFunc(app.preflight)
+
+	routes := &routeTable{Router: router, custom: map[string]map[string]http.Handler{}}
+
+	// every route is documented in the OpenAPI document; spec.check reports
+	// the routes registered without documentation. Each route records its
+	// path as the pattern metrics and traces are labelled with.
+	spec := newAPISpec()
+	route := func(method string, path string, handler http.HandlerFunc) {
+		spec.add(method, path)
+		routes.handle(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			internal.ContextRecordRoute(r, path)
+			handler(w, r)
+		}))
+	}
+
 	// health check route
-	router.HandlerFunc(http.MethodGet, "/v1/health", app.healthCheckHandler)
+	route(http.MethodGet, "/v1/health", app.healthCheckHandler)
+	route(http.MethodGet, "/v1/health/live", app.liveHandler)
+	route(http.MethodGet, "/v1/health/ready", app.readyHandler)
 
 	// groups routes
-	router.HandlerFunc(http.MethodPost, "/v1/groups", app.CreateGroupHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/groups/:id", app.GetGroupHandler)
-	router.HandlerFunc(http.MethodPut, "/v1/groups/:id", app.UpdateGroupHandler)
-	router.HandlerFunc(http.MethodDelete, "/v1/groups/:id", app.DeleteGroupHandler)
-	router.HandlerFunc(http.MethodPatch, "/v1/groups/:id", app.PatchGroupHandler)
-	router.HandlerFunc(http.MethodGet, "/v1/groups", app.GetGroupsHandler)
+	route(http.MethodPost, "/v1/groups", app.rateLimit(rateLimitWrite, app.CreateGroupHandler))
+	route(http.MethodPost, "/v1/groups:batch", app.rateLimit(rateLimitWrite, app.maxBodySize(app.Config.BatchMaxBodySize, app.BatchGroupsHandler)))
+	route(http.MethodGet, "/v1/groups/:id", app.rateLimit(rateLimitRead, app.GetGroupHandler))
+	route(http.MethodPut, "/v1/groups/:id", app.rateLimit(rateLimitWrite, app.UpdateGroupHandler))
+	route(http.MethodDelete, "/v1/groups/:id", app.rateLimit(rateLimitWrite, app.DeleteGroupHandler))
+	route(http.MethodPatch, "/v1/groups/:id", app.rateLimit(rateLimitWrite, app.PatchGroupHandler))
+	route(http.MethodGet, "/v1/groups", app.rateLimit(rateLimitRead, app.GetGroupsHandler))
+	route(http.MethodGet, "/v1/groups/:id/history", app.rateLimit(rateLimitRead, app.requireGroupMember(app.GetGroupHistoryHandler)))
+	route(http.MethodGet, "/v1/groups/:id/activity", app.rateLimit(rateLimitRead, app.requireGroupMember(app.GetGroupActivityHandler)))
+	route(http.MethodPost, "/v1/groups/:id/activity/read", app.rateLimit(rateLimitWrite, app.MarkGroupActivityReadHandler))
+	route(http.MethodGet, "/v1/groups/:id/events", app.rateLimit(rateLimitRealtime, app.requireGroupMember(app.GroupEventsHandler)))
+
+	// search routes
+	route(http.MethodGet, "/v1/search", app.rateLimit(rateLimitRead, app.SearchHandler))
+
+	// real-time routes
+	route(http.MethodGet, "/v1/ws", app.rateLimit(rateLimitRealtime, app.WebSocketHandler))
+
+	// webhook routes, restricted to the user who created each webhook
+	route(http.MethodPost, "/v1/webhooks", app.rateLimit(rateLimitWrite, app.requireUser(app.CreateWebhookHandler)))
+	route(http.MethodGet, "/v1/webhooks", app.rateLimit(rateLimitRead, app.requireUser(app.GetWebhooksHandler)))
+	route(http.MethodGet, "/v1/webhooks/:id", app.rateLimit(rateLimitRead, app.requireUser(app.GetWebhookHandler)))
+	route(http.MethodPatch, "/v1/webhooks/:id", app.rateLimit(rateLimitWrite, app.requireUser(app.PatchWebhookHandler)))
+	route(http.MethodDelete, "/v1/webhooks/:id", app.rateLimit(rateLimitWrite, app.requireUser(app.DeleteWebhookHandler)))
+	route(http.MethodGet, "/v1/webhooks/:id/deliveries", app.rateLimit(rateLimitRead, app.requireUser(app.GetWebhookDeliveriesHandler)))
+	route(http.MethodPost, "/v1/webhooks/:id/deliveries/:delivery_id/redeliver", app.rateLimit(rateLimitWrite, app.requireUser(app.RedeliverWebhookHandler)))
+
+	// audit routes
+	route(http.MethodGet, "/v1/audit", app.rateLimit(rateLimitRead, app.requireAdmin(app.GetAuditEventsHandler)))
+
+	// graphql routes, executing queries against the same models as the routes above
+	schema, err := app.graphQLSchema()
+	if err != nil {
+		panic(err)
+	}
+	route(http.MethodGet, "/v1/graphql", app.rateLimit(rateLimitRead, app.graphQLHandler(schema)))
+	route(http.MethodPost, "/v1/graphql", app.rateLimit(rateLimitWrite, app.graphQLHandler(schema)))
+
+	// documentation routes
+	route(http.MethodGet, "/v1/openapi.json", app.openAPIHandler(spec))
+	route(http.MethodGet, "/v1/docs/*filepath", app.docsHandler)
+
+	return routes, spec
+}
+
+// routeTable holds the routes of the API: those of the router, and custom
+// methods such as POST /v1/groups:batch. httprouter reads a colon anywhere in a
+// path as the start of a parameter, so custom methods are matched on their
+// exact path before the router.
+type routeTable struct {
+	*httprouter.Router
+	custom map[string]map[string]http.Handler // handlers of custom methods by path and method
+}
+
+// handle registers handler for requests with method to path.
+func (t *routeTable) handle(method string, path string, handler http.Handler) {
+	if !isCustomMethod(path) {
+		t.Handler(method, path, handler)
+		return
+	}
+
+	if t.custom[path] == nil {
+		t.custom[path] = map[string]http.Handler{}
+	}
+	t.custom[path][method] = handler
+}
+
+// ServeHTTP serves the request with the custom method registered for its path
+// and method, answering OPTIONS and other methods like the router does, or
+// hands it to the router.
+func (t *routeTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handlers, ok := t.custom[r.URL.Path]
+	if !ok {
+		t.Router.ServeHTTP(w, r)
+		return
+	}
+
+	if handler, ok := handlers[r.Method]; ok {
+		handler.ServeHTTP(w, r)
+		return
+	}
+
+	allowed := slices.Sorted(maps.Keys(handlers))
+	w.Header().Set("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+
+	if r.Method == http.MethodOptions {
+		t.GlobalOPTIONS.ServeHTTP(w, r)
+		return
+	}
+	t.MethodNotAllowed.ServeHTTP(w, r)
+}
+
+// isCustomMethod reports whether path names a custom method, whose last
+// segment is a resource followed by a colon and a verb, e.g. /v1/groups:batch.
+func isCustomMethod(path string) bool {
+	segment := path[strings.LastIndex(path, "/")+1:]
+	return strings.Index(segment, ":") > 0
+}
+
+// AdminRouter returns the handler of the admin server, which exposes operational
+// endpoints such as /metrics on a separate port from the public API.
+func (app *application) AdminRouter() http.Handler {
+	router := httprouter.New()
+
+	router.NotFound = http.HandlerFunc(internal.NotFoundError)
+	router.MethodNotAllowed = http.HandlerFunc(internal.MethodNotAllowed)
+
+	router.Handler(http.MethodGet, "/metrics", app.Metrics.Handler())
 
 	return router
 }
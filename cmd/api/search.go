@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// SearchHandler handles GET /v1/search. It returns a page of the groups
+// matching the words of q, most relevant first, with the matching words of
+// their name and description highlighted. Words are stemmed in the language
+// of the request, so that e.g. "trips" also finds "trip".
+func (app *application) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	val := internal.NewValidator(r)
+
+	filters := model.SearchQuery{
+		Q:        strings.TrimSpace(internal.ReadQueryString(r, "q", "")),
+		Page:     internal.ReadQueryInt(r, val, "page", 1),
+		PageSize: internal.ReadQueryInt(r, val, "page_size", 10),
+	}
+
+	if errors := filters.ValidateSearchQuery(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	config := model.SearchConfig(i18n.FromContext(r.Context()))
+
+	data, meta, err := app.Models.Groups.Search(r.Context(), &filters, config)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"metadata": meta,
+		"data":     data,
+	})
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"google.golang.org/grpc"
+)
+
+// workerState tracks the background workers started with application.background.
+type workerState struct {
+	workers     sync.WaitGroup
+	workersOnce sync.Once
+	workersCtx  context.Context
+	workersStop context.CancelFunc
+}
+
+// serve starts the HTTP server, and the admin and gRPC servers when their
+// addresses are configured, and blocks until they stop. On SIGINT or SIGTERM
+// the servers are shut down gracefully: the readiness probe fails at once, but
+// new requests are still served for Config.ShutdownDrain so load balancers
+// notice and stop routing them here. Then open event streams are closed
+// through the hub and in-flight requests and calls get up to 30 seconds to
+// complete.
+func (app *application) serve() error {
+	server := &http.Server{
+		Addr:    app.Config.Addr,
+		Handler: app.Router(),
+	}
+
+	var admin *http.Server
+	if app.Config.AdminAddr != "" {
+		admin = &http.Server{
+			Addr:    app.Config.AdminAddr,
+			Handler: app.AdminRouter(),
+		}
+
+		go func() {
+			internal.NewLogger().Log.Info().Str("address", admin.Addr).Msg("starting admin server")
+			if err := admin.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				internal.NewLogger().Log.Error().Err(err).Msg("admin server failed")
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if app.Config.GRPC.Addr != "" {
+		listener, err := net.Listen("tcp", app.Config.GRPC.Addr)
+		if err != nil {
+			return err
+		}
+		grpcServer = app.GRPCServer()
+
+		go func() {
+			internal.NewLogger().Log.Info().Str("address", app.Config.GRPC.Addr).Msg("starting grpc server")
+			if err := grpcServer.Serve(listener); err != nil {
+				internal.NewLogger().Log.Error().Err(err).Msg("grpc server failed")
+			}
+		}()
+	}
+
+	// event streams never finish on their own; end them when shutdown starts.
+	server.RegisterOnShutdown(app.Hub.Close)
+
+	shutdownErr := make(chan error)
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		s := <-quit
+
+		internal.NewLogger().Log.Info().Str("signal", s.String()).Msg("shutting down server")
+
+		// report not ready so load balancers stop routing new traffic here.
+		app.shuttingDown.Store(true)
+
+		// keep serving until load balancers have seen the probe fail; closing
+		// the listeners now would refuse requests still routed here.
+		time.Sleep(app.Config.ShutdownDrain)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if admin != nil {
+			admin.Shutdown(ctx)
+		}
+
+		if grpcServer != nil {
+			stopGRPC(ctx, grpcServer)
+		}
+
+		shutdownErr <- server.Shutdown(ctx)
+	}()
+
+	internal.NewLogger().Log.Info().Str("address", app.Config.Addr).Str("environment", app.Config.Env).Msg("starting server")
+
+	err := server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	if err := <-shutdownErr; err != nil {
+		return err
+	}
+
+	// stop background workers once no request can enqueue work for them.
+	app.stopBackground()
+
+	internal.NewLogger().Log.Info().Str("address", app.Config.Addr).Msg("server stopped")
+	return nil
+}
+
+// stopGRPC stops server gracefully, waiting for pending calls to complete, and
+// cancels the calls still pending when ctx is done.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// background runs fn in a goroutine tracked by the application. The context
+// passed to fn is cancelled during graceful shutdown, and shutdown waits for fn
+// to return.
+func (app *application) background(fn func(ctx context.Context)) {
+	app.workersOnce.Do(func() {
+		app.workersCtx, app.workersStop = context.WithCancel(context.Background())
+	})
+
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		fn(app.workersCtx)
+	}()
+}
+
+// stopBackground cancels every background worker and waits for them to return.
+func (app *application) stopBackground() {
+	if app.workersStop != nil {
+		app.workersStop()
+	}
+	app.workers.Wait()
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// Webhooks belong to the user who created them: every webhook route requires
+// an authenticated user, and webhooks created by other users are reported as
+// not found, except to administrators.
+
+// CreateWebhookHandler handles POST /v1/webhooks. It reads the JSON body into a
+// model.WebhookInput, validates it and returns the created webhook including its
+// secret, which is not returned again afterwards.
+func (app *application) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	input := model.WebhookInput{}
+
+	if err := internal.ReadJSON(w, r, &input); err != nil {
+		internal.BadRequestError(w, r, err.Error())
+		return
+	}
+
+	webhook := model.Webhook{
+		URL:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: input.EventTypes,
+		Active:     input.Active == nil || *input.Active,
+	}
+
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			internal.InternalServerError(w, r, err)
+			return
+		}
+		webhook.Secret = secret
+	}
+
+	// requireUser ensures there is an actor
+	actor, _ := internal.ContextGetActor(r.Context())
+	webhook.CreatedBy = &actor
+
+	val := internal.NewValidator(r)
+	if errors := webhook.Validate(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	if err := app.Models.Webhooks.Insert(r.Context(), &webhook); err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusCreated, webhook)
+}
+
+// GetWebhooksHandler handles GET /v1/webhooks. It returns a page of the
+// webhooks of the user, or of every webhook for administrators.
+func (app *application) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	val := internal.NewValidator(r)
+
+	filters := model.WebhookQuery{
+		Page:     internal.ReadQueryInt(r, val, "page", 1),
+		PageSize: internal.ReadQueryInt(r, val, "page_size", 10),
+	}
+
+	if !internal.ContextIsAdmin(r.Context()) {
+		filters.CreatedBy, _ = internal.ContextGetActor(r.Context())
+	}
+
+	if errors := filters.ValidateWebhookQuery(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	data, meta, err := app.Models.Webhooks.GetAll(r.Context(), &filters)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"metadata": meta,
+		"data":     data,
+	})
+}
+
+// GetWebhookHandler handles GET /v1/webhooks/:id. It returns the webhook
+// identified by the id URL parameter without its secret.
+func (app *application) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := app.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	webhook.Secret = ""
+	internal.WriteJSON(w, http.StatusOK, webhook)
+}
+
+// PatchWebhookHandler handles PATCH /v1/webhooks/:id. It applies the fields
+// present in the JSON body to the webhook, validates and saves it.
+func (app *application) PatchWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := app.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	input := model.WebhookUpdate{}
+	if err := internal.ReadJSON(w, r, &input); err != nil {
+		internal.BadRequestError(w, r, err.Error())
+		return
+	}
+
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+
+	if input.Secret != nil {
+		webhook.Secret = *input.Secret
+	}
+
+	if input.EventTypes != nil {
+		webhook.EventTypes = *input.EventTypes
+		if webhook.EventTypes == nil {
+			webhook.EventTypes = []string{}
+		}
+	}
+
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+
+	val := internal.NewValidator(r)
+	if errors := webhook.Validate(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	err := app.Models.Webhooks.Update(r.Context(), webhook)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	webhook.Secret = ""
+	internal.WriteJSON(w, http.StatusOK, webhook)
+}
+
+// DeleteWebhookHandler handles DELETE /v1/webhooks/:id. It deletes the webhook
+// and its delivery log.
+func (app *application) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := app.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	err := app.Models.Webhooks.Delete(r.Context(), webhook.Id)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	message := map[string]string{
+		"message": "The item was deleted successfully",
+	}
+	internal.WriteJSON(w, http.StatusOK, message)
+}
+
+// GetWebhookDeliveriesHandler handles GET /v1/webhooks/:id/deliveries. It returns
+// a page of the webhook's delivery log, newest first, optionally filtered by status.
+func (app *application) GetWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := app.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	val := internal.NewValidator(r)
+
+	filters := model.WebhookQuery{
+		WebhookId: webhook.Id,
+		Status:    internal.ReadQueryString(r, "status", ""),
+		Page:      internal.ReadQueryInt(r, val, "page", 1),
+		PageSize:  internal.ReadQueryInt(r, val, "page_size", 10),
+	}
+
+	if errors := filters.ValidateWebhookQuery(val); errors != nil {
+		internal.BadRequestError(w, r, errors)
+		return
+	}
+
+	data, meta, err := app.Models.Webhooks.GetDeliveries(r.Context(), &filters)
+	if err != nil {
+		internal.InternalServerError(w, r, err)
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusOK, map[string]any{
+		"metadata": meta,
+		"data":     data,
+	})
+}
+
+// RedeliverWebhookHandler handles POST /v1/webhooks/:id/deliveries/:delivery_id/redeliver.
+// It queues a fresh copy of the delivery, which the dispatcher sends shortly after.
+func (app *application) RedeliverWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := app.readWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryId, err := internal.ReadParamInt(r, "delivery_id")
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return
+	}
+
+	delivery, err := app.Models.Webhooks.Redeliver(r.Context(), webhook.Id, int64(deliveryId))
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	internal.WriteJSON(w, http.StatusAccepted, delivery)
+}
+
+// readWebhook loads the webhook identified by the id URL parameter, writing the
+// error response and returning false when it cannot be loaded or the user may
+// not see it. Webhooks of other users are reported as not found, so their ids
+// are not revealed.
+func (app *application) readWebhook(w http.ResponseWriter, r *http.Request) (*model.Webhook, bool) {
+	id, err := internal.ReadParamId(r)
+	if err != nil {
+		internal.NotFoundError(w, r)
+		return nil, false
+	}
+
+	webhook, err := app.Models.Webhooks.Get(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal.ErrNotFound):
+			internal.NotFoundError(w, r)
+		default:
+			internal.InternalServerError(w, r, err)
+		}
+		return nil, false
+	}
+
+	if !canAccessWebhook(r, webhook) {
+		internal.NotFoundError(w, r)
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+// canAccessWebhook reports whether the user performing r created webhook or is
+// an administrator.
+func canAccessWebhook(r *http.Request, webhook *model.Webhook) bool {
+	if internal.ContextIsAdmin(r.Context()) {
+		return true
+	}
+
+	actor, ok := internal.ContextGetActor(r.Context())
+	return ok && webhook.CreatedBy != nil && *webhook.CreatedBy == actor
+}
+
+// generateWebhookSecret returns a random 32 byte secret encoded as hex.
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/events"
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+)
+
+// WebSocket session settings.
+const (
+	wsWriteWait      = 10 * time.Second // time allowed to write a message
+	wsPongWait       = 60 * time.Second // time allowed between pongs from the client
+	wsPingPeriod     = 50 * time.Second // must be shorter than wsPongWait
+	wsMaxMessageSize = 4096             // maximum size of a client message in bytes
+	wsSendBuffer     = 64               // messages queued for a client before it is dropped
+	wsRateLimit      = 10               // client messages allowed per second
+	wsRateBurst      = 20               // client messages allowed in a burst
+)
+
+// Message types exchanged over the WebSocket connection.
+const (
+	wsTypeSubscribe    = "subscribe"
+	wsTypeUnsubscribe  = "unsubscribe"
+	wsTypePing         = "ping"
+	wsTypePong         = "pong"
+	wsTypeSubscribed   = "subscribed"
+	wsTypeUnsubscribed = "unsubscribed"
+	wsTypeActivity     = "activity"
+	wsTypePresence     = "presence"
+	wsTypeError        = "error"
+)
+
+// upgrader returns the upgrader of WebSocket connections, which only accepts
+// browser connections from the origins trusted by the CORS configuration.
+// Clients other than browsers send no Origin header and are always accepted.
+func (app *application) upgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || app.Config.CORS.allowsOrigin(origin)
+		},
+	}
+}
+
+// wsEnvelope is the JSON envelope of every message sent over the connection.
+type wsEnvelope struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsGroupPayload is the payload of subscribe and unsubscribe messages. LastEventId
+// optionally resumes a subscription after the last activity the client received.
+type wsGroupPayload struct {
+	GroupId     int    `json:"group_id"`
+	LastEventId *int64 `json:"last_event_id"`
+}
+
+// wsPresencePayload lists the users currently viewing a group.
+type wsPresencePayload struct {
+	GroupId int   `json:"group_id"`
+	Viewers []int `json:"viewers"`
+}
+
+// wsErrorPayload describes a rejected client message.
+type wsErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// wsSession holds the state of a single WebSocket connection.
+type wsSession struct {
+	app     *application
+	conn    *websocket.Conn
+	userId  int
+	lang    string // language of error messages, negotiated on the upgrade request
+	send    chan wsEnvelope
+	limiter *rate.Limiter
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu   sync.Mutex
+	subs map[int]context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// WebSocketHandler handles GET /v1/ws. It upgrades the connection to a WebSocket
+// on which a user authenticated like every other request, by a bearer token in
+// the Authorization header or, as browsers cannot set headers on WebSocket
+// requests, in the access_token query parameter, subscribes to the groups they are a member of and
+// receives the groups' activities and presence (who is currently viewing).
+func (app *application) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	userId, ok := internal.ContextGetActor(r.Context())
+	if !ok {
+		internal.UnauthorizedError(w, r, nil)
+		return
+	}
+
+	conn, err := app.upgrader().Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied to the client.
+		return
+	}
+
+	// the request context ends when the handler returns, so the session owns its own.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	session := &wsSession{
+		app:     app,
+		conn:    conn,
+		userId:  userId,
+		lang:    i18n.FromContext(r.Context()),
+		send:    make(chan wsEnvelope, wsSendBuffer),
+		limiter: rate.NewLimiter(wsRateLimit, wsRateBurst),
+		ctx:     ctx,
+		cancel:  cancel,
+		subs:    map[int]context.CancelFunc{},
+	}
+
+	go session.writeLoop()
+	session.readLoop()
+}
+
+// readLoop reads client messages until the connection fails or the session ends,
+// then releases every subscription.
+func (s *wsSession) readLoop() {
+	defer func() {
+		s.cancel()
+		s.wg.Wait()
+		s.conn.Close()
+	}()
+
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if !s.limiter.Allow() {
+			s.sendError("rate_limited", "ws.rate_limited", nil)
+			continue
+		}
+
+		msg := wsEnvelope{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.sendError("invalid_message", "ws.invalid_message", nil)
+			continue
+		}
+
+		switch msg.Type {
+		case wsTypeSubscribe:
+			s.subscribe(msg.Payload)
+		case wsTypeUnsubscribe:
+			s.unsubscribe(msg.Payload)
+		case wsTypePing:
+			s.enqueue(wsTypePong, nil)
+		default:
+			s.sendError("unknown_type", "ws.unknown_type", i18n.Args{"type": msg.Type})
+		}
+	}
+}
+
+// writeLoop writes queued messages and keep-alive pings until the session ends.
+// It closes the connection with "going away" when the server shuts down.
+func (s *wsSession) writeLoop() {
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteJSON(msg); err != nil {
+				s.cancel()
+				s.conn.Close()
+				return
+			}
+
+		case <-ping.C:
+			s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				s.cancel()
+				s.conn.Close()
+				return
+			}
+
+		case <-s.app.Hub.Done():
+			s.close(websocket.CloseGoingAway, "server shutting down")
+			return
+
+		case <-s.ctx.Done():
+			s.close(websocket.CloseNormalClosure, "")
+			return
+		}
+	}
+}
+
+// close sends a close frame and closes the connection, which also ends readLoop.
+func (s *wsSession) close(code int, text string) {
+	s.cancel()
+	message := websocket.FormatCloseMessage(code, text)
+	s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsWriteWait))
+	s.conn.Close()
+}
+
+// subscribe starts delivering a group's activities and presence to the client
+// after checking that the user is a member of the group.
+func (s *wsSession) subscribe(raw json.RawMessage) {
+	payload := wsGroupPayload{}
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.GroupId < 1 {
+		s.sendError("invalid_payload", "ws.invalid_payload", nil)
+		return
+	}
+
+	member, err := s.app.Models.Members.IsMember(s.ctx, payload.GroupId, s.userId)
+	if err != nil {
+		s.sendError("internal_error", "error.internal", nil)
+		internal.NewLogger().Log.Error().Err(err).Msg("failed to check group membership")
+		return
+	}
+	if !member {
+		s.sendError("forbidden", "ws.not_member", nil)
+		return
+	}
+
+	lastId := int64(0)
+	if payload.LastEventId != nil {
+		lastId = *payload.LastEventId
+	} else if lastId, err = s.app.Models.Activities.LatestId(s.ctx, payload.GroupId); err != nil {
+		s.sendError("internal_error", "error.internal", nil)
+		internal.NewLogger().Log.Error().Err(err).Msg("failed to read latest group event")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subs[payload.GroupId]; ok {
+		s.enqueue(wsTypeSubscribed, wsGroupPayload{GroupId: payload.GroupId})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.subs[payload.GroupId] = cancel
+
+	sub := s.app.Hub.Subscribe(payload.GroupId)
+	s.app.Hub.Join(payload.GroupId, s.userId)
+
+	s.enqueue(wsTypeSubscribed, wsGroupPayload{GroupId: payload.GroupId})
+
+	s.wg.Add(1)
+	go s.watch(ctx, sub, lastId)
+}
+
+// unsubscribe stops delivering a group's activities and presence to the client.
+func (s *wsSession) unsubscribe(raw json.RawMessage) {
+	payload := wsGroupPayload{}
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.GroupId < 1 {
+		s.sendError("invalid_payload", "ws.invalid_payload", nil)
+		return
+	}
+
+	s.mu.Lock()
+	cancel, ok := s.subs[payload.GroupId]
+	delete(s.subs, payload.GroupId)
+	s.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+
+	s.enqueue(wsTypeUnsubscribed, wsGroupPayload{GroupId: payload.GroupId})
+}
+
+// watch forwards a group's activities newer than lastId and its presence changes
+// to the client until ctx ends.
+func (s *wsSession) watch(ctx context.Context, sub *events.Subscription, lastId int64) {
+	groupId := sub.GroupId()
+
+	defer func() {
+		sub.Unsubscribe()
+		s.app.Hub.Leave(groupId, s.userId)
+		s.wg.Done()
+	}()
+
+	// deliver anything missed since lastId straight away.
+	pending := true
+
+	for {
+		if pending {
+			activities, err := s.app.Models.Activities.GetSince(ctx, groupId, lastId, sseBatchSize)
+			if err != nil {
+				if ctx.Err() == nil {
+					internal.NewLogger().Log.Error().Err(err).Int("group_id", groupId).Msg("failed to read group events")
+				}
+				return
+			}
+
+			for _, activity := range activities {
+				s.enqueue(wsTypeActivity, activity)
+				lastId = activity.Id
+
+				if activity.Type == model.ActivityGroupDeleted {
+					s.mu.Lock()
+					cancel, ok := s.subs[groupId]
+					delete(s.subs, groupId)
+					s.mu.Unlock()
+
+					if ok {
+						cancel()
+					}
+
+					s.enqueue(wsTypeUnsubscribed, wsGroupPayload{GroupId: groupId})
+					return
+				}
+			}
+
+			pending = len(activities) == sseBatchSize
+			if pending {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.C:
+			pending = true
+		case <-sub.Presence:
+			s.enqueue(wsTypePresence, wsPresencePayload{GroupId: groupId, Viewers: s.app.Hub.Viewers(groupId)})
+		}
+	}
+}
+
+// sendError queues an error message for the client, with the message of
+// message code translated to the language of the upgrade request.
+func (s *wsSession) sendError(code string, message string, args i18n.Args) {
+	s.enqueue(wsTypeError, wsErrorPayload{Code: code, Message: i18n.T(s.lang, message, args)})
+}
+
+// enqueue marshals the payload and queues a message for the client. A client
+// that does not keep up with its messages is disconnected.
+func (s *wsSession) enqueue(msgType string, payload any) {
+	msg := wsEnvelope{Type: msgType}
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			internal.NewLogger().Log.Error().Err(err).Msg("failed to marshal websocket message")
+			return
+		}
+		msg.Payload = data
+	}
+
+	select {
+	case s.send <- msg:
+	case <-s.ctx.Done():
+	default:
+		s.cancel()
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialWebSocket opens a WebSocket connection to /v1/ws of server as user 1
+// with the given headers, returning the response of the upgrade request.
+func dialWebSocket(t *testing.T, server *httptest.Server, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/ws?access_token=" + testToken(t, "1", false)
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if conn != nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, resp, err
+}
+
+func TestWebSocketCheckOrigin(t *testing.T) {
+	app := newTestApp()
+	app.Config.CORS.Origins = []string{"https://app.example.com"}
+
+	server := httptest.NewServer(app.Router())
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		origin string
+		status int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"https://app.example.com", http.StatusSwitchingProtocols},
+		{"https://evil.example.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.origin != "" {
+			header.Set("Origin", tt.origin)
+		}
+
+		_, resp, _ := dialWebSocket(t, server, header)
+		if resp == nil || resp.StatusCode != tt.status {
+			t.Errorf("origin %q: response %v, want status %d", tt.origin, resp, tt.status)
+		}
+	}
+}
+
+func TestWebSocketErrorsAreLocalized(t *testing.T) {
+	server := httptest.NewServer(newTestApp().Router())
+	t.Cleanup(server.Close)
+
+	conn, _, err := dialWebSocket(t, server, http.Header{"Accept-Language": {"fr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.WriteJSON(wsEnvelope{Type: "shout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := wsEnvelope{}
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := wsErrorPayload{}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Type de message non pris en charge : shout"; msg.Type != wsTypeError || payload.Message != want {
+		t.Errorf("message %s %+v, want error %q", msg.Type, payload, want)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/auth"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// orphansCommand defines orphans, which lists the orphaned rows and repairs
+// them in a single transaction with -fix.
+func orphansCommand(fs *flag.FlagSet) func(ctx context.Context, models *repository.Models, args []string) error {
+	fix := fs.Bool("fix", false, "Repair the rows: make the creators of groups without owner their owners and delete read markers of deleted groups")
+
+	return func(ctx context.Context, models *repository.Models, args []string) error {
+		orphans, err := models.Maintenance.FindOrphans(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KIND\tTABLE\tKEY\tDETAILS")
+		for _, orphan := range orphans {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", orphan.Kind, orphan.Table, orphan.Key, orphan.Details)
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d orphaned rows\n", len(orphans))
+		if !*fix || len(orphans) == 0 {
+			return nil
+		}
+
+		repaired, err := models.Maintenance.RepairOrphans(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, kind := range []string{model.OrphanGroupWithoutOwner, model.OrphanReadWithoutGroup} {
+			fmt.Printf("repaired %d %s rows\n", repaired[kind], kind)
+		}
+		return nil
+	}
+}
+
+// exportGroupCommand defines export-group, which writes everything stored about
+// a group as JSON. Deleted groups are exported too, without the group itself.
+func exportGroupCommand(fs *flag.FlagSet) func(ctx context.Context, models *repository.Models, args []string) error {
+	out := fs.String("out", "", "File the export is written to instead of the standard output")
+
+	return func(ctx context.Context, models *repository.Models, args []string) error {
+		if len(args) != 1 {
+			return errors.New("expected a single group ID argument")
+		}
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil || id < 1 {
+			return fmt.Errorf("invalid group ID %q", args[0])
+		}
+
+		export, err := exportGroup(ctx, models, id)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			file, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(export)
+	}
+}
+
+// exportGroup reads the group identified by id with its members, activities
+// and audit events. It returns internal.ErrNotFound when nothing was ever
+// stored about the group.
+func exportGroup(ctx context.Context, models *repository.Models, id int) (*model.GroupExport, error) {
+	export := &model.GroupExport{
+		Members:     []*model.Member{},
+		Activities:  []*model.Activity{},
+		AuditEvents: []*model.AuditEvent{},
+		ExportedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	group, err := models.Groups.Get(ctx, id)
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		export.Group = group
+	}
+
+	if export.Members, err = models.Members.GetAll(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// activities are read oldest first, a batch at a time
+	for lastId := int64(0); ; {
+		activities, err := models.Activities.GetSince(ctx, id, lastId, 500)
+		if err != nil {
+			return nil, err
+		}
+		if len(activities) == 0 {
+			break
+		}
+
+		export.Activities = append(export.Activities, activities...)
+		lastId = activities[len(activities)-1].Id
+	}
+
+	// audit events are read newest first, the largest page allowed at a time
+	filters := model.AuditQuery{EntityType: model.AuditEntityGroup, EntityId: id, Page: 1, PageSize: 100}
+	for {
+		events, meta, err := models.Audit.GetAll(ctx, &filters)
+		if err != nil {
+			return nil, err
+		}
+
+		export.AuditEvents = append(export.AuditEvents, events...)
+		if filters.Page >= meta.LastPage {
+			break
+		}
+		filters.Page++
+	}
+	slices.Reverse(export.AuditEvents)
+
+	if export.Group == nil && len(export.Activities) == 0 && len(export.AuditEvents) == 0 {
+		return nil, internal.ErrNotFound
+	}
+
+	return export, nil
+}
+
+// vacuumCommand defines vacuum.
+func vacuumCommand(fs *flag.FlagSet) func(ctx context.Context, models *repository.Models, args []string) error {
+	retention := fs.Duration("retention", 30*24*time.Hour, "How long settled outbox messages and webhook deliveries are kept")
+
+	return func(ctx context.Context, models *repository.Models, args []string) error {
+		if *retention < 0 {
+			return errors.New("the retention must not be negative")
+		}
+
+		removed, err := models.Maintenance.Vacuum(ctx, *retention)
+		for _, table := range []string{"rate_limit_buckets", "outbox", "webhook_deliveries"} {
+			if count, ok := removed[table]; ok {
+				fmt.Printf("removed %d rows from %s\n", count, table)
+			}
+		}
+
+		return err
+	}
+}
+
+// tokenCommand defines token, which prints a bearer token for the user given by
+// -user, signed with the auth_secret the API verifies tokens with.
+func tokenCommand(fs *flag.FlagSet) func(ctx context.Context, models *repository.Models, args []string) error {
+	user := fs.Int("user", 0, "Id of the user the token identifies")
+	admin := fs.Bool("admin", false, "Allow the token to use the admin routes, such as GET /v1/audit")
+	ttl := fs.Duration("ttl", 24*time.Hour, "How long the token is valid")
+
+	return func(ctx context.Context, models *repository.Models, args []string) error {
+		if *user < 1 {
+			return errors.New("-user must be a positive user ID")
+		}
+
+		if *ttl <= 0 {
+			return errors.New("-ttl must be positive")
+		}
+
+		secret, err := internal.GetString("auth_secret")
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		token, err := auth.Sign(auth.Secret(secret), auth.Claims{
+			Subject:   strconv.Itoa(*user),
+			Admin:     *admin,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(*ttl).Unix(),
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(token)
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+// Command fairshare-admin runs maintenance tasks directly against the database
+// of the API, bypassing HTTP. It reads the database settings like the API: from
+// the environment, optionally loaded from a .env file.
+//
+//	fairshare-admin orphans -fix
+//	fairshare-admin export-group -out group-3.json 3
+//	fairshare-admin vacuum -retention 720h
+//	fairshare-admin token -user 42 -ttl 24h
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/joho/godotenv"
+)
+
+// command is a subcommand of fairshare-admin.
+type command struct {
+	name    string
+	args    string // positional arguments shown in the usage
+	summary string
+	offline bool // runs without a database connection, with nil models
+	setup   func(fs *flag.FlagSet) func(ctx context.Context, models *repository.Models, args []string) error
+}
+
+// commands lists every subcommand, in the order of the usage.
+var commands = []command{
+	{"orphans", "", "List rows left inconsistent with the rest of the data, and repair them with -fix", false, orphansCommand},
+	{"export-group", "ID", "Export a group with its members, activities and audit events as JSON", false, exportGroupCommand},
+	{"vacuum", "", "Remove idle rate limit buckets and settled outbox messages and webhook deliveries", false, vacuumCommand},
+	{"token", "", "Issue a bearer token for a user, signed with auth_secret", true, tokenCommand},
+}
+
+func main() {
+	// load environmental variables
+	godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:])
+	stop()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fairshare-admin:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the subcommand named by args[0] with the rest of args.
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		usage()
+		return errors.New("missing command")
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		fs := flag.NewFlagSet("fairshare-admin "+cmd.name, flag.ExitOnError)
+		runCommand := cmd.setup(fs)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "Usage: fairshare-admin %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+			fs.PrintDefaults()
+		}
+		fs.Parse(args[1:])
+
+		if cmd.offline {
+			return runCommand(ctx, nil, fs.Args())
+		}
+
+		// create a database connection
+		dbConfig, err := repository.ConfigFromEnv()
+		if err != nil {
+			return fmt.Errorf("read database settings from environment: %w", err)
+		}
+
+		db, err := repository.New(dbConfig)
+		if err != nil {
+			return fmt.Errorf("connect to the database: %w", err)
+		}
+		defer db.Close()
+
+		return runCommand(ctx, repository.NewModels(db), fs.Args())
+	}
+
+	usage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// usage prints the subcommands.
+func usage() {
+	fmt.Fprint(os.Stderr, "Usage: fairshare-admin COMMAND [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name+" "+cmd.args, cmd.summary)
+	}
+	fmt.Fprint(os.Stderr, "\nRun fairshare-admin COMMAND -help for the flags of a command.\n")
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// completionScripts holds the completion script of each supported shell. The
+// scripts ask the binary for candidates with the hidden __complete command, so
+// they stay in sync with the commands and flags.
+var completionScripts = map[string]string{
+	"bash": `# fairshare completion for bash, load with: source <(fairshare completion bash)
+_fairshare() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	COMPREPLY=($(compgen -W "$(fairshare __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -F _fairshare fairshare
+`,
+	"zsh": `# fairshare completion for zsh, load with: source <(fairshare completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+_fairshare() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	COMPREPLY=($(compgen -W "$(fairshare __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -F _fairshare fairshare
+`,
+	"fish": `# fairshare completion for fish, load with: fairshare completion fish | source
+complete -c fairshare -f -a '(fairshare __complete (commandline -opc)[2..-1] 2>/dev/null)'
+`,
+}
+
+// completionCommand defines completion, which prints the completion script of a shell.
+func completionCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		if len(args) != 1 || completionScripts[args[0]] == "" {
+			return fmt.Errorf("expected a shell argument: %s", strings.Join(slices.Sorted(maps.Keys(completionScripts)), ", "))
+		}
+
+		_, err := io.WriteString(cli.out, completionScripts[args[0]])
+		return err
+	}
+}
+
+// complete prints the candidates completing a command line whose previous
+// words are words: the next words of matching commands, the flags of the
+// command once it is complete, or the values of the flag before the cursor.
+func complete(out io.Writer, words []string) error {
+	candidates := []string{}
+
+	if len(words) > 0 {
+		switch strings.TrimLeft(words[len(words)-1], "-") {
+		case "output":
+			candidates = []string{"table", "json", "csv"}
+		case "profile":
+			if cfg, err := loadConfig(); err == nil {
+				candidates = slices.Sorted(maps.Keys(cfg.Profiles))
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		// the words naming the command are those extending a command name
+		path := []string{}
+		for _, word := range words {
+			next := strings.Join(append(path, word), " ")
+			if !slices.ContainsFunc(commands, func(cmd command) bool {
+				return cmd.name == next || strings.HasPrefix(cmd.name, next+" ")
+			}) {
+				break
+			}
+			path = append(path, word)
+		}
+
+		if cmd, _ := findCommand(path); cmd != nil {
+			candidates = commandFlags(cmd)
+			if cmd.name == "completion" {
+				candidates = slices.Sorted(maps.Keys(completionScripts))
+			}
+		} else {
+			for _, cmd := range commands {
+				name := strings.Fields(cmd.name)
+				if len(name) > len(path) && slices.Equal(name[:len(path)], path) && !slices.Contains(candidates, name[len(path)]) {
+					candidates = append(candidates, name[len(path)])
+				}
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		fmt.Fprintln(out, candidate)
+	}
+
+	return nil
+}
+
+// commandFlags returns the flags of cmd, prefixed with --.
+func commandFlags(cmd *command) []string {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	addGlobalFlags(fs, &globalFlags{})
+	cmd.setup(fs)
+
+	flags := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "--"+f.Name)
+	})
+
+	return flags
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultURL is the address of the API when no profile or flag sets one.
+const defaultURL = "http://localhost:4000"
+
+// profile stores how to reach the API and on behalf of which user.
+type profile struct {
+	URL      string `json:"url"`
+	Token    string `json:"token,omitempty"`    // bearer token identifying the user, anonymous when empty
+	Language string `json:"language,omitempty"` // language of error messages, e.g. fr
+}
+
+// fileConfig is the content of the configuration file.
+type fileConfig struct {
+	Default  string              `json:"default,omitempty"` // profile used when none is selected
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// configPath returns the path of the configuration file: $FAIRSHARE_CONFIG, or
+// fairshare/config.json in the user configuration directory.
+func configPath() (string, error) {
+	if path := os.Getenv("FAIRSHARE_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "fairshare", "config.json"), nil
+}
+
+// loadConfig reads the configuration file, returning an empty configuration
+// when it does not exist yet.
+func loadConfig() (*fileConfig, error) {
+	cfg := &fileConfig{Profiles: map[string]*profile{}}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+
+	return cfg, nil
+}
+
+// save writes the configuration file, readable by the current user only.
+func (cfg *fileConfig) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// resolveProfile returns the settings of the selected profile, overridden by
+// the FAIRSHARE_URL, FAIRSHARE_TOKEN and FAIRSHARE_LANGUAGE environment
+// variables, then by the flags. The profile is the one named by the -profile
+// flag, $FAIRSHARE_PROFILE or the default of the configuration file.
+func resolveProfile(flags *globalFlags) (*profile, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	name := flags.Profile
+	if name == "" {
+		name = os.Getenv("FAIRSHARE_PROFILE")
+	}
+	if name == "" {
+		name = cfg.Default
+	}
+
+	settings := profile{URL: defaultURL}
+	if name != "" {
+		selected, ok := cfg.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+		settings = *selected
+	}
+
+	if value := os.Getenv("FAIRSHARE_URL"); value != "" {
+		settings.URL = value
+	}
+	if value := os.Getenv("FAIRSHARE_TOKEN"); value != "" {
+		settings.Token = value
+	}
+	if value := os.Getenv("FAIRSHARE_LANGUAGE"); value != "" {
+		settings.Language = value
+	}
+
+	if flags.URL != "" {
+		settings.URL = flags.URL
+	}
+	if flags.Token != "" {
+		settings.Token = flags.Token
+	}
+	if flags.Language != "" {
+		settings.Language = flags.Language
+	}
+
+	return &settings, nil
+}
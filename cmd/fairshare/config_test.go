@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeConfig points FAIRSHARE_CONFIG to a configuration file with the
+// profiles work and home, work being the default.
+func writeConfig(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("FAIRSHARE_CONFIG", path)
+
+	cfg := &fileConfig{
+		Default: "work",
+		Profiles: map[string]*profile{
+			"work": {URL: "https://work.example.com", Token: "work-token", Language: "en"},
+			"home": {URL: "https://home.example.com", Token: "home-token"},
+		},
+	}
+	if err := cfg.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		flags globalFlags
+		want  profile
+	}{
+		{"default profile", nil, globalFlags{},
+			profile{URL: "https://work.example.com", Token: "work-token", Language: "en"}},
+		{"profile from the environment", map[string]string{"FAIRSHARE_PROFILE": "home"}, globalFlags{},
+			profile{URL: "https://home.example.com", Token: "home-token"}},
+		{"profile flag over the environment", map[string]string{"FAIRSHARE_PROFILE": "home"}, globalFlags{Profile: "work"},
+			profile{URL: "https://work.example.com", Token: "work-token", Language: "en"}},
+		{"environment over the profile", map[string]string{"FAIRSHARE_URL": "https://env.example.com", "FAIRSHARE_LANGUAGE": "fr"}, globalFlags{},
+			profile{URL: "https://env.example.com", Token: "work-token", Language: "fr"}},
+		{"flags over the environment", map[string]string{"FAIRSHARE_TOKEN": "env-token", "FAIRSHARE_LANGUAGE": "fr"}, globalFlags{Token: "flag-token", Language: "en"},
+			profile{URL: "https://work.example.com", Token: "flag-token", Language: "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t)
+			for _, key := range []string{"FAIRSHARE_PROFILE", "FAIRSHARE_URL", "FAIRSHARE_TOKEN", "FAIRSHARE_LANGUAGE"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			got, err := resolveProfile(&tt.flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tt.want {
+				t.Errorf("resolveProfile = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveProfileWithoutConfig(t *testing.T) {
+	t.Setenv("FAIRSHARE_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	for _, key := range []string{"FAIRSHARE_PROFILE", "FAIRSHARE_URL", "FAIRSHARE_TOKEN", "FAIRSHARE_LANGUAGE"} {
+		t.Setenv(key, "")
+	}
+
+	got, err := resolveProfile(&globalFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URL != defaultURL || got.Token != "" {
+		t.Errorf("resolveProfile = %+v, want the default URL without a token", *got)
+	}
+
+	if _, err := resolveProfile(&globalFlags{Profile: "work"}); err == nil {
+		t.Error("resolveProfile succeeded for an unknown profile")
+	}
+
+	if _, err := os.Stat(os.Getenv("FAIRSHARE_CONFIG")); err == nil {
+		t.Error("resolveProfile created the configuration file")
+	}
+}
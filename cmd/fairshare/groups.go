@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Abdul4code/FairShare/pkg/client"
+)
+
+// currencies lists the currencies the API accepts, for the usage of flags.
+const currencies = "Dollar, Euro, Pound or Naira"
+
+// listGroupsCommand defines groups list, which accepts the query parameters of
+// GET /v1/groups. With -all it follows the pages until the last one.
+func listGroupsCommand(fs *flag.FlagSet) runFunc {
+	params := client.ListGroupsParams{}
+	all := false
+
+	fs.StringVar(&params.Name, "name", "", "Groups whose name contains the value")
+	fs.StringVar(&params.Currency, "currency", "", "Groups using the currency, or one of a comma separated list of currencies: "+currencies)
+	fs.StringVar(&params.Description, "description", "", "Groups whose description matches the words of the value")
+	fs.StringVar(&params.Filter, "filter", "", "Filter expression, e.g. \"currency in (Euro, Pound) and created_at >= 2025-01-01\"")
+	fs.StringVar(&params.Sort, "sort", "", "Comma separated fields to sort by: name, currency, created_at or id, prefixed with - for descending order")
+	fs.IntVar(&params.Page, "page", 0, "Page to list, the first by default")
+	fs.IntVar(&params.PageSize, "page-size", 0, "Groups per page, 10 by default")
+	fs.BoolVar(&all, "all", false, "List the groups of every page from -page on")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments %v", args)
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		if all {
+			groups := []*client.Group{}
+			for group, err := range api.Groups(ctx, params) {
+				if err != nil {
+					return err
+				}
+				groups = append(groups, group)
+			}
+			return cli.print(groups, table{groupHeader, groupRows(groups...)})
+		}
+
+		page, err := api.ListGroups(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if err := cli.print(page, table{groupHeader, groupRows(page.Data...)}); err != nil {
+			return err
+		}
+
+		if cli.flags.Output == "table" {
+			meta := page.Metadata
+			fmt.Fprintf(os.Stderr, "\npage %d of %d, %d groups\n", meta.CurrentPage, meta.LastPage, meta.Total)
+		}
+		return nil
+	}
+}
+
+// getGroupCommand defines groups get.
+func getGroupCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		group, err := api.GetGroup(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(group, table{groupHeader, groupRows(group)})
+	}
+}
+
+// createGroupCommand defines groups create.
+func createGroupCommand(fs *flag.FlagSet) runFunc {
+	input := client.GroupInput{}
+
+	fs.StringVar(&input.Name, "name", "", "Name of the group (required)")
+	fs.StringVar(&input.Currency, "currency", "", "Currency of the group: "+currencies+" (required)")
+	fs.StringVar(&input.Description, "description", "", "Description of the group")
+	fs.IntVar(&input.CreatedBy, "created-by", 0, "Id of the user creating the group")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments %v", args)
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		group, err := api.CreateGroup(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(group, table{groupHeader, groupRows(group)})
+	}
+}
+
+// updateGroupCommand defines groups update, which replaces the group like PUT
+// /v1/groups/:id.
+func updateGroupCommand(fs *flag.FlagSet) runFunc {
+	input := client.GroupInput{}
+
+	fs.StringVar(&input.Name, "name", "", "Name of the group (required)")
+	fs.StringVar(&input.Currency, "currency", "", "Currency of the group: "+currencies+" (required)")
+	fs.StringVar(&input.Description, "description", "", "Description of the group")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		group, err := api.UpdateGroup(ctx, id, input)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(group, table{groupHeader, groupRows(group)})
+	}
+}
+
+// patchGroupCommand defines groups patch, which only sends the fields whose
+// flag is given.
+func patchGroupCommand(fs *flag.FlagSet) runFunc {
+	input := client.GroupUpdate{}
+
+	fs.Func("name", "New name of the group", func(value string) error {
+		input.Name = &value
+		return nil
+	})
+	fs.Func("currency", "New currency of the group: "+currencies, func(value string) error {
+		input.Currency = &value
+		return nil
+	})
+	fs.Func("description", "New description of the group", func(value string) error {
+		input.Description = &value
+		return nil
+	})
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		if input == (client.GroupUpdate{}) {
+			return errors.New("nothing to change, give at least one of -name, -currency or -description")
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		group, err := api.PatchGroup(ctx, id, input)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(group, table{groupHeader, groupRows(group)})
+	}
+}
+
+// deleteGroupCommand defines groups delete.
+func deleteGroupCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		if err := api.DeleteGroup(ctx, id); err != nil {
+			return err
+		}
+
+		return cli.printMessage(fmt.Sprintf("group %d deleted", id))
+	}
+}
+
+// groupHistoryCommand defines groups history.
+func groupHistoryCommand(fs *flag.FlagSet) runFunc {
+	params := client.HistoryParams{}
+
+	fs.Func("from", "Events created at or after the time, as RFC 3339 or YYYY-MM-DD", func(value string) (err error) {
+		params.From, err = parseTime(value)
+		return err
+	})
+	fs.Func("to", "Events created before the time, as RFC 3339 or YYYY-MM-DD", func(value string) (err error) {
+		params.To, err = parseTime(value)
+		return err
+	})
+	fs.IntVar(&params.Page, "page", 0, "Page to list, the first by default")
+	fs.IntVar(&params.PageSize, "page-size", 0, "Events per page, 10 by default")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		page, err := api.GroupHistory(ctx, id, params)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(page, table{auditHeader, auditRows(page.Data...)})
+	}
+}
+
+// groupActivityCommand defines groups activity.
+func groupActivityCommand(fs *flag.FlagSet) runFunc {
+	params := client.ActivityParams{}
+
+	fs.Int64Var(&params.Cursor, "cursor", 0, "Id of the last activity already seen, the newest activity by default")
+	fs.IntVar(&params.Limit, "limit", 0, "Activities to list, 20 by default")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		page, err := api.GroupActivity(ctx, id, params)
+		if err != nil {
+			return err
+		}
+
+		if err := cli.print(page, table{activityHeader, activityRows(page.Data...)}); err != nil {
+			return err
+		}
+
+		if cli.flags.Output == "table" && page.Metadata.NextCursor != nil {
+			fmt.Fprintf(os.Stderr, "\nmore with -cursor %d\n", *page.Metadata.NextCursor)
+		}
+		return nil
+	}
+}
+
+// markActivityReadCommand defines groups read.
+func markActivityReadCommand(fs *flag.FlagSet) runFunc {
+	var lastReadId *int64
+
+	fs.Func("last-read-id", "Id of the last activity read, the newest activity by default", func(value string) error {
+		id, err := strconv.ParseInt(value, 10, 64)
+		lastReadId = &id
+		return err
+	})
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		marker, err := api.MarkActivityRead(ctx, id, lastReadId)
+		if err != nil {
+			return err
+		}
+
+		return cli.print(map[string]int64{"last_read_id": marker}, table{
+			header: []string{"LAST_READ_ID"},
+			rows:   [][]string{{fmt.Sprint(marker)}},
+		})
+	}
+}
+
+// groupEventsCommand defines groups events, which prints each activity of the
+// group as it happens until interrupted or the group is deleted. The json
+// output format prints one JSON object per line.
+func groupEventsCommand(fs *flag.FlagSet) runFunc {
+	lastEventId := int64(-1)
+
+	fs.Int64Var(&lastEventId, "last-event-id", -1, "Id of the last activity already seen, to receive the activities after it first")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		id, err := argId(args)
+		if err != nil {
+			return err
+		}
+
+		api, err := cli.client()
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(cli.out)
+		w := csv.NewWriter(cli.out)
+		if cli.flags.Output == "csv" {
+			w.Write(activityHeader)
+			w.Flush()
+		}
+
+		for activity, err := range api.GroupEvents(ctx, id, lastEventId) {
+			if err != nil {
+				return err
+			}
+
+			switch cli.flags.Output {
+			case "json":
+				err = enc.Encode(activity)
+			case "csv":
+				w.WriteAll(activityRows(activity))
+				err = w.Error()
+			default:
+				_, err = fmt.Fprintf(cli.out, "%s  #%d  %s  %s\n", activity.CreatedAt, activity.Id, activity.Type, activity.Message)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// parseTime parses an RFC 3339 timestamp or a YYYY-MM-DD date.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, errors.New("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,247 @@
+// Command fairshare is a command-line client of the FairShare API.
+//
+//	fairshare groups list --currency=Euro --sort=currency,-created_at
+//	fairshare groups create --name Trip --currency Euro
+//	fairshare groups patch 3 --name "Summer trip" --output json
+//
+// Settings are read from named profiles, see fairshare profile set.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Abdul4code/FairShare/pkg/client"
+)
+
+// globalFlags stores the flags accepted by every command
+type globalFlags struct {
+	Profile  string // profile of the configuration file to use
+	URL      string // API address, overriding the profile
+	Token    string // bearer token of the user to act as, overriding the profile
+	Language string // language of error messages, overriding the profile
+	Output   string // output format: table | json | csv
+}
+
+// runFunc runs a command with its positional arguments once its flags are parsed.
+type runFunc func(ctx context.Context, cli *cli, args []string) error
+
+// command is a command of the CLI, such as groups list.
+type command struct {
+	name    string                         // words invoking the command
+	args    string                         // positional arguments shown in the usage, e.g. ID
+	summary string                         // one line description shown in the usage
+	setup   func(fs *flag.FlagSet) runFunc // defines the flags of the command and returns how to run it
+}
+
+// commands lists every command of the CLI, in the order of the usage.
+var commands = []command{
+	{"groups list", "", "List groups, filtered and sorted", listGroupsCommand},
+	{"groups get", "ID", "Show a group", getGroupCommand},
+	{"groups create", "", "Create a group", createGroupCommand},
+	{"groups update", "ID", "Replace the name, currency and description of a group", updateGroupCommand},
+	{"groups patch", "ID", "Change some fields of a group", patchGroupCommand},
+	{"groups delete", "ID", "Delete a group", deleteGroupCommand},
+	{"groups history", "ID", "List the audit events of a group", groupHistoryCommand},
+	{"groups activity", "ID", "List the activity feed of a group", groupActivityCommand},
+	{"groups read", "ID", "Mark the activity feed of a group as read", markActivityReadCommand},
+	{"groups events", "ID", "Stream the activities of a group as they happen", groupEventsCommand},
+	{"profile list", "", "List the profiles of the configuration file", listProfilesCommand},
+	{"profile set", "NAME", "Create or change a profile with the -url, -token and -lang flags", setProfileCommand},
+	{"profile use", "NAME", "Make a profile the default", useProfileCommand},
+	{"profile delete", "NAME", "Delete a profile", deleteProfileCommand},
+	{"completion", "bash|zsh|fish", "Print the shell completion script", completionCommand},
+}
+
+// cli holds what commands need to run: the parsed global flags, the output and
+// the API client.
+type cli struct {
+	flags globalFlags
+	out   io.Writer
+	api   *client.Client
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Stdout)
+	stop()
+
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// printError prints err, with the message of each invalid field on its own line.
+func printError(w io.Writer, err error) {
+	apiErr := &client.APIError{}
+	if !errors.As(err, &apiErr) || len(apiErr.Fields) == 0 {
+		fmt.Fprintln(w, "fairshare:", strings.TrimPrefix(err.Error(), "fairshare: "))
+		return
+	}
+
+	fmt.Fprintf(w, "fairshare: the request is invalid (%d):\n", apiErr.StatusCode)
+	for _, field := range slices.Sorted(maps.Keys(apiErr.Fields)) {
+		fmt.Fprintf(w, "  %s: %s\n", field, apiErr.Fields[field])
+	}
+}
+
+// errUsage is returned when the command line is invalid, after the usage is printed.
+var errUsage = errors.New("invalid usage")
+
+// run runs the command named by args, writing its output to out.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) > 0 && args[0] == "__complete" {
+		return complete(out, args[1:])
+	}
+
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		usage(os.Stderr, args)
+		return errUsage
+	}
+
+	cli := &cli{out: out}
+
+	fs := flag.NewFlagSet("fairshare "+cmd.name, flag.ContinueOnError)
+	addGlobalFlags(fs, &cli.flags)
+	runCommand := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: fairshare %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseFlags(fs, rest)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+
+	if !slices.Contains([]string{"table", "json", "csv"}, cli.flags.Output) {
+		return fmt.Errorf("unknown output format %q, want table, json or csv", cli.flags.Output)
+	}
+
+	return runCommand(ctx, cli, positional)
+}
+
+// findCommand returns the command named by the first words of args and the
+// remaining arguments, or nil when no command matches.
+func findCommand(args []string) (*command, []string) {
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) >= len(words) && slices.Equal(args[:len(words)], words) {
+			return &commands[i], args[len(words):]
+		}
+	}
+	return nil, nil
+}
+
+// usage prints the commands starting with the words of args, or every command.
+func usage(w io.Writer, args []string) {
+	fmt.Fprint(w, "Usage: fairshare COMMAND [flags] [arguments]\n\nCommands:\n")
+
+	prefix := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		prefix = args[0] + " "
+	}
+
+	for _, cmd := range commands {
+		if prefix == "" || strings.HasPrefix(cmd.name+" ", prefix) {
+			fmt.Fprintf(w, "  %-32s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.summary)
+		}
+	}
+
+	fmt.Fprint(w, "\nRun fairshare COMMAND -help for the flags of a command.\n")
+}
+
+// addGlobalFlags defines the flags accepted by every command.
+func addGlobalFlags(fs *flag.FlagSet, flags *globalFlags) {
+	fs.StringVar(&flags.Profile, "profile", "", "Profile of the configuration file to use")
+	fs.StringVar(&flags.URL, "url", "", "API address, overriding the profile")
+	fs.StringVar(&flags.Token, "token", "", "Bearer token of the user to act as, overriding the profile")
+	fs.StringVar(&flags.Language, "lang", "", "Language of error messages, overriding the profile")
+	fs.StringVar(&flags.Output, "output", "table", "Output format. table|json|csv")
+}
+
+// parseFlags parses the flags of args, which may come before, between or after
+// the positional arguments, and returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// client returns the API client configured by the selected profile and flags.
+func (cli *cli) client() (*client.Client, error) {
+	if cli.api != nil {
+		return cli.api, nil
+	}
+
+	settings, err := resolveProfile(&cli.flags)
+	if err != nil {
+		return nil, err
+	}
+
+	api, err := client.New(
+		settings.URL,
+		client.WithToken(settings.Token),
+		client.WithLanguage(settings.Language),
+	)
+	if err != nil {
+		return nil, err
+	}
+	api.UserAgent = "fairshare-cli"
+
+	cli.api = api
+	return api, nil
+}
+
+// argId returns the single positional argument as a positive integer id.
+func argId(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected a single ID argument, got %d arguments", len(args))
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid ID %q, must be a positive integer", args[0])
+	}
+
+	return id, nil
+}
+
+// argName returns the single positional argument.
+func argName(args []string) (string, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", fmt.Errorf("expected a single NAME argument, got %d arguments", len(args))
+	}
+
+	return args[0], nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		name string
+		rest []string
+	}{
+		{[]string{"groups", "list"}, "groups list", []string{}},
+		{[]string{"groups", "get", "3", "--output", "json"}, "groups get", []string{"3", "--output", "json"}},
+		{[]string{"profile", "set", "work", "-url", "https://api.example.com"}, "profile set", []string{"work", "-url", "https://api.example.com"}},
+		{[]string{"completion", "bash"}, "completion", []string{"bash"}},
+		{[]string{"groups"}, "", nil},
+		{[]string{"groups", "rename", "3"}, "", nil},
+		{[]string{"list", "groups"}, "", nil},
+		{nil, "", nil},
+	}
+
+	for _, tt := range tests {
+		cmd, rest := findCommand(tt.args)
+
+		name := ""
+		if cmd != nil {
+			name = cmd.name
+		}
+		if name != tt.name || !slices.Equal(rest, tt.rest) {
+			t.Errorf("findCommand(%q) = %q %q, want %q %q", tt.args, name, rest, tt.name, tt.rest)
+		}
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		output     string
+		currency   string
+	}{
+		{"flags first", []string{"--output", "json", "--currency=Euro", "3"}, []string{"3"}, "json", "Euro"},
+		{"flags last", []string{"3", "-output=csv", "-currency", "Pound"}, []string{"3"}, "csv", "Pound"},
+		{"interleaved", []string{"3", "--output", "json", "4", "--currency", "Naira", "5"}, []string{"3", "4", "5"}, "json", "Naira"},
+		{"terminator", []string{"--output", "json", "--", "--currency", "3"}, []string{"--currency", "3"}, "json", ""},
+		{"no arguments", []string{}, []string{}, "table", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := globalFlags{}
+			currency := ""
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			addGlobalFlags(fs, &flags)
+			fs.StringVar(&currency, "currency", "", "")
+
+			positional, err := parseFlags(fs, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(positional, tt.positional) || flags.Output != tt.output || currency != tt.currency {
+				t.Errorf("parseFlags = %q with output %q and currency %q, want %q with %q and %q",
+					positional, flags.Output, currency, tt.positional, tt.output, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParseFlagsUnknownFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	addGlobalFlags(fs, &globalFlags{})
+
+	if _, err := parseFlags(fs, []string{"3", "--colour", "red"}); err == nil {
+		t.Error("parseFlags succeeded, want an error for the unknown flag")
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Abdul4code/FairShare/pkg/client"
+)
+
+// table is the tabular form of a command result, used by the table and csv
+// output formats.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// print writes the result of a command in the output format: value as JSON,
+// or t as an aligned table or CSV.
+func (cli *cli) print(value any, t table) error {
+	switch cli.flags.Output {
+	case "json":
+		enc := json.NewEncoder(cli.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case "csv":
+		w := csv.NewWriter(cli.out)
+		w.Write(t.header)
+		w.WriteAll(t.rows)
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(cli.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
+
+// printMessage writes a confirmation message, as {"message": ...} in the json
+// output format.
+func (cli *cli) printMessage(message string) error {
+	if cli.flags.Output == "json" {
+		return cli.print(map[string]string{"message": message}, table{})
+	}
+
+	_, err := fmt.Fprintln(cli.out, message)
+	return err
+}
+
+// groupHeader is the header of the tabular form of groups.
+var groupHeader = []string{"ID", "NAME", "CURRENCY", "DESCRIPTION", "CREATED_BY", "CREATED_AT", "VERSION"}
+
+// groupRows returns the tabular form of groups.
+func groupRows(groups ...*client.Group) [][]string {
+	rows := make([][]string, 0, len(groups))
+
+	for _, group := range groups {
+		rows = append(rows, []string{
+			strconv.Itoa(group.Id),
+			group.Name,
+			group.Currency,
+			group.Description,
+			strconv.Itoa(group.CreatedBy),
+			group.CreatedAt,
+			strconv.Itoa(group.Version),
+		})
+	}
+
+	return rows
+}
+
+// auditHeader is the header of the tabular form of audit events.
+var auditHeader = []string{"ID", "ACTION", "ACTOR", "REQUEST_ID", "CREATED_AT", "BEFORE", "AFTER"}
+
+// auditRows returns the tabular form of audit events.
+func auditRows(events ...*client.AuditEvent) [][]string {
+	rows := make([][]string, 0, len(events))
+
+	for _, event := range events {
+		rows = append(rows, []string{
+			strconv.FormatInt(event.Id, 10),
+			event.Action,
+			optionalInt(event.ActorId),
+			event.RequestId,
+			event.CreatedAt,
+			string(event.Before),
+			string(event.After),
+		})
+	}
+
+	return rows
+}
+
+// activityHeader is the header of the tabular form of activities.
+var activityHeader = []string{"ID", "TYPE", "ACTOR", "MESSAGE", "CREATED_AT"}
+
+// activityRows returns the tabular form of activities.
+func activityRows(activities ...*client.Activity) [][]string {
+	rows := make([][]string, 0, len(activities))
+
+	for _, activity := range activities {
+		rows = append(rows, []string{
+			strconv.FormatInt(activity.Id, 10),
+			activity.Type,
+			optionalInt(activity.ActorId),
+			activity.Message,
+			activity.CreatedAt,
+		})
+	}
+
+	return rows
+}
+
+// optionalInt formats i, or - when nil.
+func optionalInt(i *int) string {
+	if i == nil {
+		return "-"
+	}
+	return strconv.Itoa(*i)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/pkg/client"
+)
+
+func TestPrint(t *testing.T) {
+	actor := 1
+	group := &client.Group{Id: 3, Name: "Trip, Lisbon", Currency: "Euro", CreatedBy: 1, CreatedAt: "2026-01-01T00:00:00Z", Version: 2}
+	activity := &client.Activity{Id: 7, Type: "group_created", ActorId: &actor, Message: "created", CreatedAt: "2026-01-01T00:00:00Z"}
+	system := &client.Activity{Id: 8, Type: "group_renamed", Message: "renamed", CreatedAt: "2026-01-02T00:00:00Z"}
+
+	tests := []struct {
+		name   string
+		output string
+		value  any
+		table  table
+		want   string
+	}{
+		{"table", "table", group, table{groupHeader, groupRows(group)}, "" +
+			"ID  NAME          CURRENCY  DESCRIPTION  CREATED_BY  CREATED_AT            VERSION\n" +
+			"3   Trip, Lisbon  Euro                   1           2026-01-01T00:00:00Z  2\n"},
+		{"csv quotes commas", "csv", group, table{groupHeader, groupRows(group)}, "" +
+			"ID,NAME,CURRENCY,DESCRIPTION,CREATED_BY,CREATED_AT,VERSION\n" +
+			"3,\"Trip, Lisbon\",Euro,,1,2026-01-01T00:00:00Z,2\n"},
+		{"json", "json", group, table{groupHeader, groupRows(group)}, "" +
+			"{\n" +
+			"  \"id\": 3,\n" +
+			"  \"name\": \"Trip, Lisbon\",\n" +
+			"  \"currency\": \"Euro\",\n" +
+			"  \"description\": \"\",\n" +
+			"  \"created_by\": 1,\n" +
+			"  \"created_at\": \"2026-01-01T00:00:00Z\",\n" +
+			"  \"version\": 2\n" +
+			"}\n"},
+		{"csv without actor", "csv", nil, table{activityHeader, activityRows(activity, system)}, "" +
+			"ID,TYPE,ACTOR,MESSAGE,CREATED_AT\n" +
+			"7,group_created,1,created,2026-01-01T00:00:00Z\n" +
+			"8,group_renamed,-,renamed,2026-01-02T00:00:00Z\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &strings.Builder{}
+			cli := &cli{flags: globalFlags{Output: tt.output}, out: out}
+
+			if err := cli.print(tt.value, tt.table); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("print wrote\n%s\nwant\n%s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintMessage(t *testing.T) {
+	tests := map[string]string{
+		"table": "group 3 deleted\n",
+		"csv":   "group 3 deleted\n",
+		"json":  "{\n  \"message\": \"group 3 deleted\"\n}\n",
+	}
+
+	for output, want := range tests {
+		out := &strings.Builder{}
+		cli := &cli{flags: globalFlags{Output: output}, out: out}
+
+		if err := cli.printMessage("group 3 deleted"); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != want {
+			t.Errorf("%s: printMessage wrote %q, want %q", output, out, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// listProfilesCommand defines profile list.
+func listProfilesCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		rows := [][]string{}
+		for _, name := range slices.Sorted(maps.Keys(cfg.Profiles)) {
+			p := cfg.Profiles[name]
+
+			isDefault := ""
+			if name == cfg.Default {
+				isDefault = "*"
+			}
+
+			// never print the token itself
+			token := ""
+			if p.Token != "" {
+				token = "set"
+			}
+
+			rows = append(rows, []string{isDefault, name, p.URL, token, p.Language})
+		}
+
+		return cli.print(cfg, table{[]string{"DEFAULT", "NAME", "URL", "TOKEN", "LANGUAGE"}, rows})
+	}
+}
+
+// setProfileCommand defines profile set, which creates a profile or saves the
+// -url, -token and -lang flags given into it. The first profile becomes the
+// default.
+func setProfileCommand(fs *flag.FlagSet) runFunc {
+	makeDefault := false
+
+	fs.BoolVar(&makeDefault, "default", false, "Make the profile the default")
+
+	return func(ctx context.Context, cli *cli, args []string) error {
+		name, err := argName(args)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			p = &profile{URL: defaultURL}
+			cfg.Profiles[name] = p
+		}
+
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "url":
+				p.URL = cli.flags.URL
+			case "token":
+				p.Token = cli.flags.Token
+			case "lang":
+				p.Language = cli.flags.Language
+			}
+		})
+
+		if makeDefault || cfg.Default == "" {
+			cfg.Default = name
+		}
+
+		if err := cfg.save(); err != nil {
+			return err
+		}
+
+		return cli.printMessage(fmt.Sprintf("profile %s saved", name))
+	}
+}
+
+// useProfileCommand defines profile use.
+func useProfileCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		name, err := argName(args)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+
+		cfg.Default = name
+		if err := cfg.save(); err != nil {
+			return err
+		}
+
+		return cli.printMessage(fmt.Sprintf("profile %s is now the default", name))
+	}
+}
+
+// deleteProfileCommand defines profile delete.
+func deleteProfileCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, cli *cli, args []string) error {
+		name, err := argName(args)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := cfg.Profiles[name]; !ok {
+			return fmt.Errorf("unknown profile %q", name)
+		}
+
+		delete(cfg.Profiles, name)
+		if cfg.Default == name {
+			cfg.Default = ""
+		}
+
+		if err := cfg.save(); err != nil {
+			return err
+		}
+
+		return cli.printMessage(fmt.Sprintf("profile %s deleted", name))
+	}
+}
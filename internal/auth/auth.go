@@ -0,0 +1,132 @@
+// Package auth issues and verifies the bearer tokens identifying the users of
+// the API. Tokens are JSON Web Tokens signed with HMAC-SHA256 (HS256) under a
+// secret shared by the API servers and whoever issues the tokens; a token
+// signed with any other key or algorithm is rejected.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinSecretSize is the smallest secret tokens may be signed with, in bytes.
+const MinSecretSize = 32
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, signed with
+	// another secret or algorithm, or that do not identify a user.
+	ErrInvalidToken = errors.New("auth: invalid token")
+
+	// ErrExpiredToken is returned for tokens used after their expiry.
+	ErrExpiredToken = errors.New("auth: token expired")
+
+	// ErrShortSecret is returned when signing or verifying with a secret
+	// shorter than MinSecretSize.
+	ErrShortSecret = errors.New("auth: secret shorter than 32 bytes")
+)
+
+// Secret is the key tokens are signed with. It is never printed, so that
+// configurations holding it can be logged.
+type Secret []byte
+
+// String hides the secret.
+func (s Secret) String() string {
+	if len(s) == 0 {
+		return ""
+	}
+	return "[redacted]"
+}
+
+// Claims are the statements a token makes about its holder.
+type Claims struct {
+	Subject   string `json:"sub"`             // id of the user
+	Admin     bool   `json:"admin,omitempty"` // whether the user may use the admin routes
+	IssuedAt  int64  `json:"iat,omitempty"`   // Unix time the token was issued at
+	ExpiresAt int64  `json:"exp"`             // Unix time after which the token is rejected
+}
+
+// UserId returns the id of the user the claims are about.
+func (c Claims) UserId() (int, error) {
+	id, err := strconv.Atoi(c.Subject)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidToken
+	}
+	return id, nil
+}
+
+// header is the JOSE header of every token.
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Sign returns a token holding claims, signed with secret.
+func Sign(secret Secret, claims Claims) (string, error) {
+	if len(secret) < MinSecretSize {
+		return "", ErrShortSecret
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signature(secret, unsigned), nil
+}
+
+// Verify checks token was signed with secret and has not expired at now, and
+// returns its claims. Tokens without an expiry or a user id are invalid.
+func Verify(secret Secret, token string, now time.Time) (Claims, error) {
+	if len(secret) < MinSecretSize {
+		return Claims{}, ErrShortSecret
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	// compare the whole header so that no other algorithm, including none, is accepted
+	if parts[0] != header {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(secret, parts[0]+"."+parts[1]))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	claims := Claims{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if _, err := claims.UserId(); err != nil {
+		return Claims{}, err
+	}
+
+	if claims.ExpiresAt == 0 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return Claims{}, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+// signature returns the HS256 signature of the unsigned token.
+func signature(secret Secret, unsigned string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,196 @@
+// Package compress compresses HTTP responses with the gzip or brotli content
+// coding negotiated through the Accept-Encoding request header.
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/andybalholm/brotli"
+)
+
+// supported content codings, in order of preference
+const (
+	Brotli = "br"
+	Gzip   = "gzip"
+)
+
+// MinSize is the size below which responses are sent uncompressed; compressing
+// them costs more than it saves.
+const MinSize = 1024
+
+// Negotiate returns the content coding the response to r should use, or ""
+// when the client accepts neither brotli nor gzip.
+func Negotiate(r *http.Request) string {
+	header := r.Header.Get("Accept-Encoding")
+	if header == "" {
+		return ""
+	}
+
+	return internal.Negotiate(header, Brotli, Gzip)
+}
+
+// Writer compresses the body written to it with a content coding. The first
+// MinSize bytes are buffered so small responses are sent uncompressed; larger
+// and flushed responses are compressed as they are written, without buffering
+// the whole body. Responses that already carry a Content-Encoding, event
+// streams and bodiless responses are passed through unchanged.
+//
+// Close must be called once the handler returns.
+type Writer struct {
+	http.ResponseWriter
+
+	encoding    string
+	status      int            // status written by the handler, zero until then
+	buf         []byte         // body held back until MinSize is reached
+	encoder     io.WriteCloser // compressing writer, nil until compression starts
+	passthrough bool           // the body is written uncompressed
+	hijacked    bool
+}
+
+// NewWriter returns a Writer compressing the body written to w with encoding,
+// one of Brotli or Gzip.
+func NewWriter(w http.ResponseWriter, encoding string) *Writer {
+	return &Writer{ResponseWriter: w, encoding: encoding}
+}
+
+// WriteHeader records the status code. It is sent to the wrapped writer once
+// the writer knows whether the body is compressed.
+func (cw *Writer) WriteHeader(status int) {
+	if status < http.StatusOK && status != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+
+	h := cw.Header()
+	if status == http.StatusNoContent ||
+		status == http.StatusNotModified ||
+		status == http.StatusSwitchingProtocols ||
+		h.Get("Content-Encoding") != "" ||
+		strings.HasPrefix(h.Get("Content-Type"), "text/event-stream") {
+		cw.passthrough = true
+		cw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// Write compresses b, or holds it back while the body is smaller than MinSize.
+func (cw *Writer) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	switch {
+	case cw.passthrough:
+		return cw.ResponseWriter.Write(b)
+	case cw.encoder != nil:
+		return cw.encoder.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= MinSize {
+		if err := cw.start(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// start sends the headers announcing the compressed body and compresses the
+// buffered bytes.
+func (cw *Writer) start() error {
+	h := cw.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", cw.encoding)
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case Brotli:
+		// a low level keeps brotli fast enough for responses streamed row by row
+		cw.encoder = brotli.NewWriterLevel(cw.ResponseWriter, 4)
+	default:
+		cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+
+	_, err := cw.encoder.Write(buf)
+	return err
+}
+
+// Flush compresses and sends everything written so far, then flushes the
+// wrapped writer. It starts compression even when less than MinSize bytes were
+// written, since a flushing handler streams its body.
+func (cw *Writer) Flush() {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if !cw.passthrough && !cw.hijacked {
+		if cw.encoder == nil {
+			if err := cw.start(); err != nil {
+				return
+			}
+		}
+
+		if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+			if err := f.Flush(); err != nil {
+				return
+			}
+		}
+	}
+
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the compressed body, or sends a body smaller than MinSize
+// uncompressed.
+func (cw *Writer) Close() error {
+	switch {
+	case cw.hijacked || cw.passthrough || cw.status == 0:
+		return nil
+	case cw.encoder != nil:
+		return cw.encoder.Close()
+	}
+
+	cw.passthrough = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	return err
+}
+
+// Hijack implements http.Hijacker when the wrapped writer does, so WebSocket
+// upgrades work through the writer.
+func (cw *Writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer does not support hijacking")
+	}
+
+	cw.hijacked = true
+	return h.Hijack()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (cw *Writer) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKey is an unexported type for keys stored on a request context so
+// values set by this package cannot collide with keys from other packages.
+type contextKey string
+
+const (
+	actorContextKey     = contextKey("actor")
+	adminContextKey     = contextKey("admin")
+	requestIDContextKey = contextKey("request_id")
+	maxBodyContextKey   = contextKey("max_body_size")
+	routeContextKey     = contextKey("route")
+)
+
+// ContextSetActor returns a copy of the request with the id of the user
+// performing the request attached to its context.
+func ContextSetActor(r *http.Request, actor int) *http.Request {
+	return r.WithContext(ContextWithActor(r.Context(), actor))
+}
+
+// ContextWithActor returns a copy of ctx carrying the id of the user
+// performing the request, for requests that are not HTTP requests.
+func ContextWithActor(ctx context.Context, actor int) context.Context {
+	return context.WithValue(ctx, actorContextKey, actor)
+}
+
+// ContextGetActor returns the id of the user performing the request and
+// whether one was set. Anonymous requests return false.
+func ContextGetActor(ctx context.Context) (int, bool) {
+	actor, ok := ctx.Value(actorContextKey).(int)
+	return actor, ok
+}
+
+// ContextSetAdmin returns a copy of the request marking the user performing
+// the request as an administrator.
+func ContextSetAdmin(r *http.Request) *http.Request {
+	return r.WithContext(ContextWithAdmin(r.Context()))
+}
+
+// ContextWithAdmin returns a copy of ctx marking the user performing the
+// request as an administrator, for requests that are not HTTP requests.
+func ContextWithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminContextKey, true)
+}
+
+// ContextIsAdmin reports whether the user performing the request is an
+// administrator.
+func ContextIsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey).(bool)
+	return admin
+}
+
+// ContextSetRequestID returns a copy of the request with the given request id
+// attached to its context.
+func ContextSetRequestID(r *http.Request, id string) *http.Request {
+	return r.WithContext(ContextWithRequestID(r.Context(), id))
+}
+
+// ContextWithRequestID returns a copy of ctx carrying the given request id,
+// for requests that are not HTTP requests.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// ContextGetRequestID returns the request id attached to the context or an
+// empty string when none is set.
+func ContextGetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// ContextSetMaxBodySize returns a copy of the request allowing ReadJSON to read
+// bodies of up to size bytes instead of DefaultMaxBodySize.
+func ContextSetMaxBodySize(r *http.Request, size int64) *http.Request {
+	ctx := context.WithValue(r.Context(), maxBodyContextKey, size)
+	return r.WithContext(ctx)
+}
+
+// ContextGetMaxBodySize returns the largest body ReadJSON reads for the
+// request, DefaultMaxBodySize unless the route set another limit.
+func ContextGetMaxBodySize(ctx context.Context) int64 {
+	if size, ok := ctx.Value(maxBodyContextKey).(int64); ok {
+		return size
+	}
+	return DefaultMaxBodySize
+}
+
+// ContextSetRoute returns a copy of the request whose context holds a place
+// where the route serving the request records its pattern with
+// ContextRecordRoute. Middleware running before the router reads the pattern
+// back with ContextGetRoute once the request was served. The request is
+// returned unchanged when it already has such a place.
+func ContextSetRoute(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeContextKey).(*string); ok {
+		return r
+	}
+
+	ctx := context.WithValue(r.Context(), routeContextKey, new(string))
+	return r.WithContext(ctx)
+}
+
+// ContextRecordRoute records the pattern of the route serving the request,
+// e.g. /v1/groups/:id, in the place set by ContextSetRoute.
+func ContextRecordRoute(r *http.Request, pattern string) {
+	if route, ok := r.Context().Value(routeContextKey).(*string); ok {
+		*route = pattern
+	}
+}
+
+// ContextGetRoute returns the pattern of the route that served the request of
+// the context, or an empty string when no route did.
+func ContextGetRoute(ctx context.Context) string {
+	if route, ok := ctx.Value(routeContextKey).(*string); ok {
+		return *route
+	}
+	return ""
+}
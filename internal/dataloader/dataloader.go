@@ -0,0 +1,76 @@
+// Package dataloader batches the lookups made while a response is resolved, so
+// that loading a field of every item in a list takes one query instead of one
+// query per item.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// FetchFunc loads the values of keys in one batch. Keys missing from the
+// returned map resolve to the zero value.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys passed to Load and fetches them together the first
+// time one of their values is needed. Values are cached for the lifetime of
+// the loader, so a loader is meant to serve a single request.
+type Loader[K comparable, V any] struct {
+	fetch FetchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	results map[K]*result[V]
+}
+
+// result holds the outcome of loading a key.
+type result[V any] struct {
+	value   V
+	err     error
+	fetched bool
+}
+
+// New returns a loader fetching values with fetch.
+func New[K comparable, V any](fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		results: map[K]*result[V]{},
+	}
+}
+
+// Load queues key and returns a thunk returning its value. The first thunk
+// called fetches every key queued so far in a single batch, so callers should
+// queue all the keys of a level before calling any thunk.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = &result[V]{}
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		res := l.results[key]
+		if !res.fetched {
+			l.dispatch(ctx)
+		}
+
+		return res.value, res.err
+	}
+}
+
+// dispatch fetches the pending keys and records their results. It must be
+// called with the lock held.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		res := l.results[key]
+		res.value, res.err, res.fetched = values[key], err, true
+	}
+}
@@ -2,10 +2,11 @@ package internal
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/Abdul4code/FairShare/internal/i18n"
 	"github.com/rs/zerolog"
 )
 
@@ -17,6 +18,16 @@ type Logger struct {
 // ErrNotFound is returned when a requested item could not be found
 var ErrNotFound = errors.New("the requested Item is not found")
 
+// ErrEditConflict is returned when an item was changed by someone else since
+// the version being updated was read
+var ErrEditConflict = errors.New("the item was modified by another request")
+
+// ErrUnauthorized is returned when a request needs an identified user
+var ErrUnauthorized = errors.New("authentication required")
+
+// ErrForbidden is returned when the user of a request may not access an item
+var ErrForbidden = errors.New("access to the item is not allowed")
+
 // LogError writes the given data to stdout (using zerolog) and appends a
 // JSON Lines (jsonl) entry to errors.jsonl in the repository root.
 // The jsonl entry includes a UTC timestamp, the formatted error string and
@@ -61,7 +72,7 @@ func NotFoundError(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	WriteError(w, http.StatusNotFound, "The requested resource Not found")
+	WriteError(w, http.StatusNotFound, i18n.T(i18n.FromContext(r.Context()), "error.not_found", nil))
 }
 
 // MethodNotAllowed is a helper function to write a 405 Method Not Allowed error response.
@@ -69,11 +80,12 @@ func MethodNotAllowed(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	message := fmt.Sprintf("The method %s is not allowed on path %s", r.Method, r.URL)
+	message := i18n.T(i18n.FromContext(r.Context()), "error.method_not_allowed", i18n.Args{"method": r.Method, "path": r.URL})
 	WriteError(w, http.StatusMethodNotAllowed, message)
 }
 
 // BadRequestError is a helper function to write a 400 Bad Request error response.
+// err is written as given, so it should already be in the language of the request.
 func BadRequestError(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -88,7 +100,7 @@ func InternalServerError(
 	r *http.Request,
 	err error,
 ) {
-	WriteError(w, http.StatusInternalServerError, "Unexpected internal server error")
+	WriteError(w, http.StatusInternalServerError, i18n.T(i18n.FromContext(r.Context()), "error.internal", nil))
 	logger := NewLogger()
 	logger.Log.Error().Err(err).Msg("internal server error")
 }
@@ -99,7 +111,27 @@ func UnauthorizedError(
 	r *http.Request,
 	err any,
 ) {
-	WriteError(w, http.StatusUnauthorized, "Unauthorized Error: Authentication required")
+	WriteError(w, http.StatusUnauthorized, i18n.T(i18n.FromContext(r.Context()), "error.unauthorized", nil))
+}
+
+// InvalidTokenError is a helper function to write a 401 Unauthorized error
+// response when the bearer token of a request cannot be verified.
+func InvalidTokenError(
+	w http.ResponseWriter,
+	r *http.Request,
+	err any,
+) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	WriteError(w, http.StatusUnauthorized, i18n.T(i18n.FromContext(r.Context()), "error.invalid_token", nil))
+}
+
+// ForbiddenError is a helper function to write a 403 Forbidden error response
+// when the authenticated user may not perform the request.
+func ForbiddenError(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	WriteError(w, http.StatusForbidden, i18n.T(i18n.FromContext(r.Context()), "error.forbidden", nil))
 }
 
 // DuplicateError is a helper function to write a 409 Conflict error response.
@@ -108,5 +140,33 @@ func DuplicateError(
 	r *http.Request,
 	err any,
 ) {
-	WriteError(w, http.StatusConflict, "This Item already exists")
+	WriteError(w, http.StatusConflict, i18n.T(i18n.FromContext(r.Context()), "error.duplicate", nil))
+}
+
+// EditConflictError is a helper function to write a 409 Conflict error response
+// when an update is based on an outdated version of an item.
+func EditConflictError(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	WriteError(w, http.StatusConflict, i18n.T(i18n.FromContext(r.Context()), "error.edit_conflict", nil))
+}
+
+// RateLimitExceededError is a helper function to write a 429 Too Many Requests error response.
+func RateLimitExceededError(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	WriteError(w, http.StatusTooManyRequests, i18n.T(i18n.FromContext(r.Context()), "error.rate_limited", nil))
+}
+
+// NotAcceptableError is a helper function to write a 406 Not Acceptable error response
+// when the resource has no representation the client accepts.
+func NotAcceptableError(
+	w http.ResponseWriter,
+	r *http.Request,
+	offers []string,
+) {
+	message := i18n.T(i18n.FromContext(r.Context()), "error.not_acceptable", i18n.Args{"offers": strings.Join(offers, ", ")})
+	WriteError(w, http.StatusNotAcceptable, message)
 }
@@ -0,0 +1,188 @@
+package events
+
+import (
+	"slices"
+	"sync"
+)
+
+// NotifyChannel is the Postgres NOTIFY channel on which the id of a changed
+// group is published when a write to it commits.
+const NotifyChannel = "group_events"
+
+// Hub fans out change notifications for groups to in-process subscribers and
+// keeps track of which users are currently viewing each group.
+//
+// A notification only tells a subscriber that something in the group changed;
+// subscribers read the changes themselves, so notifications are coalesced and
+// a slow subscriber never blocks a publisher.
+type Hub struct {
+	mu      sync.Mutex
+	subs    map[int]map[*Subscription]struct{}
+	viewers map[int]map[int]int // group id -> user id -> open views
+	closed  bool
+	done    chan struct{}
+}
+
+// Subscription receives change notifications for a single group.
+type Subscription struct {
+	// C receives a value whenever the group changed since the last receive.
+	C <-chan struct{}
+	// Presence receives a value whenever the group's viewers changed since the last receive.
+	Presence <-chan struct{}
+
+	hub      *Hub
+	groupId  int
+	c        chan struct{}
+	presence chan struct{}
+}
+
+// NewHub creates and returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{
+		subs:    map[int]map[*Subscription]struct{}{},
+		viewers: map[int]map[int]int{},
+		done:    make(chan struct{}),
+	}
+}
+
+// Subscribe registers a subscription for changes to the given group. Callers
+// must call Unsubscribe once they are done with it.
+func (h *Hub) Subscribe(groupId int) *Subscription {
+	c := make(chan struct{}, 1)
+	presence := make(chan struct{}, 1)
+	sub := &Subscription{C: c, Presence: presence, hub: h, groupId: groupId, c: c, presence: presence}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subs[groupId] == nil {
+		h.subs[groupId] = map[*Subscription]struct{}{}
+	}
+	h.subs[groupId][sub] = struct{}{}
+
+	return sub
+}
+
+// GroupId returns the id of the group the subscription receives changes for.
+func (s *Subscription) GroupId() int {
+	return s.groupId
+}
+
+// Unsubscribe removes the subscription from its hub.
+func (s *Subscription) Unsubscribe() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	delete(s.hub.subs[s.groupId], s)
+	if len(s.hub.subs[s.groupId]) == 0 {
+		delete(s.hub.subs, s.groupId)
+	}
+}
+
+// Publish notifies every subscriber of the given group that it changed.
+func (h *Hub) Publish(groupId int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs[groupId] {
+		notify(sub.c)
+	}
+}
+
+// PublishAll notifies every subscriber of every group. It is used after a
+// notification source reconnects, when changes may have been missed.
+func (h *Hub) PublishAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subs {
+		for sub := range subs {
+			notify(sub.c)
+		}
+	}
+}
+
+// Join records that the user started viewing the group and notifies the
+// group's subscribers when the set of viewers changed.
+func (h *Hub) Join(groupId int, userId int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.viewers[groupId] == nil {
+		h.viewers[groupId] = map[int]int{}
+	}
+	h.viewers[groupId][userId]++
+
+	if h.viewers[groupId][userId] == 1 {
+		h.publishPresence(groupId)
+	}
+}
+
+// Leave records that the user stopped viewing the group and notifies the
+// group's subscribers when the set of viewers changed.
+func (h *Hub) Leave(groupId int, userId int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.viewers[groupId][userId] == 0 {
+		return
+	}
+	h.viewers[groupId][userId]--
+
+	if h.viewers[groupId][userId] == 0 {
+		delete(h.viewers[groupId], userId)
+		if len(h.viewers[groupId]) == 0 {
+			delete(h.viewers, groupId)
+		}
+		h.publishPresence(groupId)
+	}
+}
+
+// Viewers returns the sorted ids of the users currently viewing the group.
+func (h *Hub) Viewers(groupId int) []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	viewers := []int{}
+	for userId := range h.viewers[groupId] {
+		viewers = append(viewers, userId)
+	}
+	slices.Sort(viewers)
+
+	return viewers
+}
+
+// publishPresence notifies the group's subscribers that its viewers changed.
+// The caller must hold h.mu.
+func (h *Hub) publishPresence(groupId int) {
+	for sub := range h.subs[groupId] {
+		notify(sub.presence)
+	}
+}
+
+// Done returns a channel that is closed when the hub shuts down. Long lived
+// subscribers such as event streams must end once it is closed.
+func (h *Hub) Done() <-chan struct{} {
+	return h.done
+}
+
+// Close shuts the hub down, signalling every subscriber through Done.
+// It is safe to call Close more than once.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+}
+
+// notify performs a non-blocking send, coalescing with a pending notification.
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
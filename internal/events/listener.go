@@ -0,0 +1,70 @@
+package events
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/lib/pq"
+)
+
+// Listener forwards Postgres notifications published on NotifyChannel to a Hub,
+// so writes committed by any API instance reach subscribers of every instance.
+type Listener struct {
+	listener *pq.Listener
+	hub      *Hub
+}
+
+// NewListener opens a dedicated connection to the database identified by dsn
+// and starts listening on NotifyChannel.
+func NewListener(dsn string, hub *Hub) (*Listener, error) {
+	logger := internal.NewLogger()
+
+	report := func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("group events listener connection problem")
+		}
+	}
+
+	l := pq.NewListener(dsn, 10*time.Second, time.Minute, report)
+	if err := l.Listen(NotifyChannel); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	return &Listener{listener: l, hub: hub}, nil
+}
+
+// Run forwards notifications to the hub until the listener is closed.
+func (l *Listener) Run() {
+	for {
+		select {
+		case n, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+
+			// a nil notification means the connection was re-established and
+			// notifications may have been lost; wake every subscriber to catch up.
+			if n == nil {
+				l.hub.PublishAll()
+				continue
+			}
+
+			groupId, err := strconv.Atoi(n.Extra)
+			if err != nil {
+				continue
+			}
+			l.hub.Publish(groupId)
+
+		case <-time.After(90 * time.Second):
+			// check the connection is still alive; Ping triggers a reconnect when not.
+			go l.listener.Ping()
+		}
+	}
+}
+
+// Close stops listening and closes the underlying connection.
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
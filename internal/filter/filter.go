@@ -0,0 +1,164 @@
+// Package filter parses the filter expressions of list endpoints, such as
+//
+//	currency in (Euro, Pound) and (created_at >= 2025-01-01 or version > 1)
+//
+// and compiles them to parameterized SQL conditions. Expressions may only
+// refer to the fields whitelisted by the endpoint, and values are always
+// passed as query arguments, never written into the SQL.
+//
+// A comparison is a field, an operator and a value: =, !=, <, <=, >, >=,
+// ~ (contains, ignoring case) or in followed by a parenthesized list of
+// values. Values are bare words or quoted with ' or ". Comparisons are
+// combined with and, or, not and parentheses; and binds tighter than or.
+package filter
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of the values a field is compared with.
+type Type int
+
+const (
+	// String fields are compared as text, and support ~.
+	String Type = iota
+	// Int fields are compared as integers, and support ordering.
+	Int
+	// Time fields are compared with RFC 3339 timestamps or YYYY-MM-DD dates,
+	// and support ordering.
+	Time
+)
+
+// Field describes a field expressions may refer to.
+type Field struct {
+	Column string   // column compared in SQL
+	Type   Type     // type of the values
+	Values []string // values the field is restricted to, if any
+}
+
+// operators returns the operators the field supports.
+func (f Field) operators() []string {
+	switch {
+	case len(f.Values) > 0:
+		return []string{"=", "!=", "in"}
+	case f.Type == String:
+		return []string{"=", "!=", "~", "in"}
+	case f.Type == Time:
+		return []string{"=", "!=", "<", "<=", ">", ">="}
+	default:
+		return []string{"=", "!=", "<", "<=", ">", ">=", "in"}
+	}
+}
+
+// Fields whitelists the fields of a resource by the name used in expressions.
+type Fields map[string]Field
+
+// Limits of an expression.
+const (
+	MaxConditions = 20  // comparisons in an expression
+	MaxValues     = 100 // values listed after in
+)
+
+// Expr is a parsed expression.
+type Expr interface {
+	compile(c *compiler)
+}
+
+// logical combines two expressions with AND or OR.
+type logical struct {
+	op          string
+	left, right Expr
+}
+
+// negation negates an expression.
+type negation struct {
+	expr Expr
+}
+
+// comparison compares the column of a field with one or more values.
+type comparison struct {
+	column string
+	op     string
+	values []any
+}
+
+// SQL returns the SQL condition of expr and its arguments, numbered from the
+// placeholder $first.
+func SQL(expr Expr, first int) (string, []any) {
+	c := &compiler{first: first}
+	expr.compile(c)
+	return c.sql.String(), c.args
+}
+
+// compiler accumulates the SQL of an expression and its arguments.
+type compiler struct {
+	sql   strings.Builder
+	args  []any
+	first int
+}
+
+// placeholder adds value to the arguments and returns its placeholder.
+func (c *compiler) placeholder(value any) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(c.first+len(c.args)-1)
+}
+
+func (e logical) compile(c *compiler) {
+	c.sql.WriteString("(")
+	e.left.compile(c)
+	c.sql.WriteString(" " + e.op + " ")
+	e.right.compile(c)
+	c.sql.WriteString(")")
+}
+
+func (e negation) compile(c *compiler) {
+	c.sql.WriteString("NOT ")
+	e.expr.compile(c)
+}
+
+func (e comparison) compile(c *compiler) {
+	switch e.op {
+	case "in":
+		placeholders := make([]string, len(e.values))
+		for i, value := range e.values {
+			placeholders[i] = c.placeholder(value)
+		}
+		c.sql.WriteString("(" + e.column + " IN (" + strings.Join(placeholders, ", ") + "))")
+	case "~":
+		pattern := "%" + likeEscaper.Replace(e.values[0].(string)) + "%"
+		c.sql.WriteString("(" + e.column + " ILIKE " + c.placeholder(pattern) + ")")
+	case "!=":
+		c.sql.WriteString("(" + e.column + " <> " + c.placeholder(e.values[0]) + ")")
+	default:
+		c.sql.WriteString("(" + e.column + " " + e.op + " " + c.placeholder(e.values[0]) + ")")
+	}
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// parseValue converts the text of a value to the type of field.
+func parseValue(field Field, text string) (any, bool) {
+	switch field.Type {
+	case Int:
+		n, err := strconv.ParseInt(text, 10, 64)
+		return n, err == nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, text); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.DateOnly, text); err == nil {
+			return t, true
+		}
+		return nil, false
+	}
+
+	if len(field.Values) > 0 && !slices.Contains(field.Values, text) {
+		return nil, false
+	}
+
+	return text, true
+}
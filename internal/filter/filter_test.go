@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"id":         {Column: "id", Type: Int},
+	"name":       {Column: "name", Type: String},
+	"currency":   {Column: "currency", Type: String, Values: []string{"Euro", "Pound"}},
+	"created_at": {Column: "created_at", Type: Time},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		sql   string
+		args  []any
+	}{
+		{"id = 3", "(id = $2)", []any{int64(3)}},
+		{"id != 3", "(id <> $2)", []any{int64(3)}},
+		{"name = 'Trip to Lisbon'", "(name = $2)", []any{"Trip to Lisbon"}},
+		{`name = "it's"`, "(name = $2)", []any{"it's"}},
+		{"name ~ 50%_off", "(name ILIKE $2)", []any{`%50\%\_off%`}},
+		{"currency IN (Euro, Pound)", "(currency IN ($2, $3))", []any{"Euro", "Pound"}},
+		{"created_at >= 2025-01-01", "(created_at >= $2)", []any{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{"created_at < 2025-01-01T12:00:00Z", "(created_at < $2)", []any{time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}},
+		{"not id = 1", "NOT (id = $2)", []any{int64(1)}},
+		{
+			"id = 1 or id = 2 and id = 3",
+			"((id = $2) OR ((id = $3) AND (id = $4)))",
+			[]any{int64(1), int64(2), int64(3)},
+		},
+		{
+			"(id = 1 or id = 2) and not (name ~ x)",
+			"(((id = $2) OR (id = $3)) AND NOT (name ILIKE $4))",
+			[]any{int64(1), int64(2), "%x%"},
+		},
+		{
+			// values are never written into the SQL
+			"name = 'x); DROP TABLE groups; --'",
+			"(name = $2)",
+			[]any{"x); DROP TABLE groups; --"},
+		},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input, testFields)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+
+		sql, args := SQL(expr, 2)
+		if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Parse(%q) = %s %v, want %s %v", tt.input, sql, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		code  string
+	}{
+		{"", "filter.end"},
+		{"id =", "filter.end"},
+		{"id = 1 and", "filter.end"},
+		{"(id = 1", "filter.end"},
+		{"id = 1)", "filter.unexpected"},
+		{"id 1", "filter.unexpected"},
+		{"and = 1", "filter.unexpected"},
+		{"id = and", "filter.unexpected"},
+		{"name = 'open", "filter.unterminated"},
+		{"secret = 1", "filter.field"},
+		{"id ~ 1", "filter.operator"},
+		{"created_at in (2025-01-01)", "filter.operator"},
+		{"currency ~ Eu", "filter.operator"},
+		{"id = one", "filter.integer"},
+		{"created_at > yesterday", "filter.time"},
+		{"currency = Yen", "filter.oneof"},
+		{strings.Repeat("id = 1 or ", MaxConditions) + "id = 1", "filter.too_many"},
+		{"id in (" + strings.Repeat("1, ", MaxValues) + "1)", "filter.too_many_values"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input, testFields)
+
+		filterErr := &Error{}
+		if !errors.As(err, &filterErr) || filterErr.Code != tt.code {
+			t.Errorf("Parse(%q) error = %v, want code %s", tt.input, err, tt.code)
+		}
+	}
+}
+
+func TestErrorMessageListsFields(t *testing.T) {
+	_, err := Parse("secret = 1", testFields)
+	if err == nil {
+		t.Fatal("Parse succeeded, want an error")
+	}
+
+	// unknown fields are reported with the sorted whitelist
+	if msg := err.Error(); !strings.Contains(msg, "created_at, currency, id, name") {
+		t.Errorf("Error() = %q, want the allowed fields", msg)
+	}
+}
@@ -0,0 +1,311 @@
+package filter
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
+)
+
+// Error reports an invalid expression. Its message is translated through the
+// i18n catalogs under Code, formatted with Args; callers add the {field} the
+// expression was read from.
+type Error struct {
+	Code string
+	Args i18n.Args
+}
+
+// Error returns the message of the error in the default language.
+func (e *Error) Error() string {
+	args := i18n.Args{"field": "filter"}
+	maps.Copy(args, e.Args)
+	return i18n.T(i18n.DefaultLanguage, e.Code, args)
+}
+
+// token kinds
+const (
+	tokenEnd = iota
+	tokenWord
+	tokenQuoted
+	tokenSymbol
+)
+
+// token is a lexical unit of an expression. pos is the position of its first
+// character, counted in characters from 1.
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// is reports whether the token is the keyword or symbol s. Keywords are
+// matched ignoring case, and never quoted.
+func (t token) is(s string) bool {
+	return (t.kind == tokenWord || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+// symbols lists the operators and punctuation of expressions, longest first.
+var symbols = []string{"!=", "<=", ">=", "=", "<", ">", "~", "(", ")", ","}
+
+// comparators lists the symbols comparing a field with a single value.
+var comparators = []string{"=", "!=", "<", "<=", ">", ">=", "~"}
+
+// keywords cannot be used as field names or bare values.
+var keywords = []string{"and", "or", "not", "in"}
+
+// lex splits input into tokens, ending with a tokenEnd.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		if r == '"' || r == '\'' {
+			end := slices.Index(runes[i+1:], r)
+			if end < 0 {
+				return nil, &Error{Code: "filter.unterminated", Args: i18n.Args{"pos": i + 1}}
+			}
+			tokens = append(tokens, token{kind: tokenQuoted, text: string(runes[i+1 : i+1+end]), pos: i + 1})
+			i += end + 2
+			continue
+		}
+
+		if symbol := symbolAt(runes[i:]); symbol != "" {
+			tokens = append(tokens, token{kind: tokenSymbol, text: symbol, pos: i + 1})
+			i += len(symbol)
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' && runes[i] != '\'' && symbolAt(runes[i:]) == "" {
+			i++
+		}
+		tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start + 1})
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(runes) + 1}), nil
+}
+
+// symbolAt returns the symbol runes start with, or "".
+func symbolAt(runes []rune) string {
+	for _, symbol := range symbols {
+		if strings.HasPrefix(string(runes[:min(len(runes), 2)]), symbol) {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// Parse parses an expression over fields. It returns an *Error when the
+// expression is malformed, refers to a field missing from fields, compares a
+// field with an unsupported operator or value, or has more than MaxConditions
+// comparisons.
+func Parse(input string, fields Fields) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, p.unexpected(t)
+	}
+
+	return expr, nil
+}
+
+// parser is a recursive descent parser over the tokens of an expression:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field operator value | field "in" "(" value { "," value } ")"
+type parser struct {
+	tokens     []token
+	next       int
+	fields     Fields
+	conditions int
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// advance consumes and returns the next token.
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+	return t
+}
+
+// expect consumes the next token when it is the symbol s.
+func (p *parser) expect(s string) error {
+	if t := p.advance(); !t.is(s) {
+		return p.unexpected(t)
+	}
+	return nil
+}
+
+// unexpected returns the error reporting an unexpected token.
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEnd {
+		return &Error{Code: "filter.end"}
+	}
+	return &Error{Code: "filter.unexpected", Args: i18n.Args{"token": t.text, "pos": t.pos}}
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("or") {
+		p.advance()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("and") {
+		p.advance()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) unary() (Expr, error) {
+	switch t := p.peek(); {
+	case t.is("not"):
+		p.advance()
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negation{expr: expr}, nil
+	case t.is("("):
+		p.advance()
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	name := p.advance()
+	if name.kind != tokenWord || isKeyword(name.text) {
+		return nil, p.unexpected(name)
+	}
+
+	field, ok := p.fields[name.text]
+	if !ok {
+		return nil, &Error{Code: "filter.field", Args: i18n.Args{"name": name.text, "fields": strings.Join(slices.Sorted(maps.Keys(p.fields)), ", ")}}
+	}
+
+	p.conditions++
+	if p.conditions > MaxConditions {
+		return nil, &Error{Code: "filter.too_many", Args: i18n.Args{"max": MaxConditions}}
+	}
+
+	op := p.advance()
+	if !(op.kind == tokenSymbol && slices.Contains(comparators, op.text)) && !op.is("in") {
+		return nil, p.unexpected(op)
+	}
+
+	operator := strings.ToLower(op.text)
+	if !slices.Contains(field.operators(), operator) {
+		return nil, &Error{Code: "filter.operator", Args: i18n.Args{"name": name.text, "operator": op.text, "operators": strings.Join(field.operators(), ", ")}}
+	}
+
+	expr := comparison{column: field.Column, op: operator}
+
+	if operator != "in" {
+		value, err := p.value(name.text, field)
+		if err != nil {
+			return nil, err
+		}
+		expr.values = append(expr.values, value)
+		return expr, nil
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	for {
+		value, err := p.value(name.text, field)
+		if err != nil {
+			return nil, err
+		}
+		expr.values = append(expr.values, value)
+
+		if len(expr.values) > MaxValues {
+			return nil, &Error{Code: "filter.too_many_values", Args: i18n.Args{"max": MaxValues}}
+		}
+
+		if !p.peek().is(",") {
+			break
+		}
+		p.advance()
+	}
+
+	return expr, p.expect(")")
+}
+
+// value consumes a value compared with the field of the given name.
+func (p *parser) value(name string, field Field) (any, error) {
+	t := p.advance()
+	if t.kind != tokenQuoted && (t.kind != tokenWord || isKeyword(t.text)) {
+		return nil, p.unexpected(t)
+	}
+
+	value, ok := parseValue(field, t.text)
+	if !ok {
+		code := map[Type]string{String: "filter.oneof", Int: "filter.integer", Time: "filter.time"}[field.Type]
+		return nil, &Error{Code: code, Args: i18n.Args{"name": name, "value": t.text, "values": strings.Join(field.Values, ", ")}}
+	}
+
+	return value, nil
+}
+
+// isKeyword reports whether a word is a keyword, ignoring case.
+func isKeyword(word string) bool {
+	return slices.ContainsFunc(keywords, func(keyword string) bool { return strings.EqualFold(keyword, word) })
+}
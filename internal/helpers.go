@@ -4,15 +4,23 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Abdul4code/FairShare/internal/i18n"
 	"github.com/Abdul4code/FairShare/internal/validation"
 	"github.com/julienschmidt/httprouter"
 )
 
 // ReadParamId reads the "id" URL parameter from the request and converts it to an integer.
 func ReadParamId(r *http.Request) (int, error) {
+	return ReadParamInt(r, "id")
+}
+
+// ReadParamInt reads the named URL parameter from the request and converts it to an integer.
+// It returns ErrNotFound when the parameter is missing or not an integer.
+func ReadParamInt(r *http.Request, key string) (int, error) {
 	params := httprouter.ParamsFromContext(r.Context())
-	value := params.ByName("id")
+	value := params.ByName(key)
 
 	if value == "" {
 		return 0, ErrNotFound
@@ -26,6 +34,11 @@ func ReadParamId(r *http.Request) (int, error) {
 	return intValue, nil
 }
 
+// NewValidator returns a validator reporting messages in the language of the request.
+func NewValidator(r *http.Request) *validation.Validator {
+	return validation.NewLocalized(i18n.FromContext(r.Context()))
+}
+
 // ReadQueryInt reads values from query strings and returns them as integer
 func ReadQueryInt(
 	r *http.Request,
@@ -45,7 +58,7 @@ func ReadQueryInt(
 	// add verification error and return default if converstion fails
 	int_value, err := strconv.Atoi(value)
 	if err != nil {
-		val.Add(key, "value must be integer")
+		val.AddCode(key, "query.integer", i18n.Args{"field": key})
 		return defaultVal
 	}
 
@@ -68,7 +81,36 @@ func ReadQueryString(
 	return value
 }
 
-// ReadQueryString reads values from query strings and returns them as string
+// ReadQueryTime reads an RFC 3339 timestamp or a YYYY-MM-DD date from the query
+// string. It adds a validation error and returns the default on malformed input.
+func ReadQueryTime(
+	r *http.Request,
+	val *validation.Validator,
+	key string,
+	defaultVal time.Time,
+) time.Time {
+	queries := r.URL.Query()
+	value := queries.Get(key)
+
+	if value == "" {
+		return defaultVal
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t
+	}
+
+	val.AddCode(key, "query.time", i18n.Args{"field": key})
+	return defaultVal
+}
+
+// ReadQueryCSV reads a comma separated list from query strings, e.g.
+// currency=Euro,Pound, and returns its values with surrounding spaces trimmed
+// and empty values dropped
 func ReadQueryCSV(
 	r *http.Request,
 	key string,
@@ -81,24 +123,42 @@ func ReadQueryCSV(
 		return defaultVal
 	}
 
-	value_csv := strings.Split(value, ",")
+	value_csv := SplitCSV(value)
+	if len(value_csv) == 0 {
+		return defaultVal
+	}
 
 	return value_csv
 }
 
+// SplitCSV splits a comma separated list, trimming surrounding spaces from its
+// values and dropping empty ones
+func SplitCSV(value string) []string {
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
 // GetSortValue returns the sort value passed as a query string without its direction
-// takes in string ie name- and return name
+// takes in string ie -name or name- and return name
 func GetSortValue(value string) string {
-	val := strings.TrimSuffix(value, "-")
-	val = strings.TrimSuffix(val, "+")
+	val := strings.TrimLeft(value, "+-")
+	if val == value {
+		val = strings.TrimRight(val, "+-")
+	}
 
 	return val
 }
 
 // GetSortDirection returns the sort direction passed as a query string without its value
-// takes in string ie name- and return DESC
+// takes in string ie -name or name- and return DESC
 func GetSortDirection(value string) string {
-	if strings.HasSuffix(value, "-") {
+	if strings.HasPrefix(value, "-") || (strings.HasSuffix(value, "-") && !strings.HasPrefix(value, "+")) {
 		return "DESC"
 	}
 
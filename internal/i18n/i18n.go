@@ -0,0 +1,173 @@
+// Package i18n translates the messages of the API. Messages are looked up by
+// stable codes (e.g. error.not_found) in catalogs embedded from the locales
+// directory, one JSON file per language named after its code (e.g. fr.json).
+// Adding a language only takes adding its catalog.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when the client accepts no supported language, and
+// for codes missing from the catalog of the requested language.
+const DefaultLanguage = "en"
+
+// Args holds the values of the {placeholders} of a message.
+type Args map[string]any
+
+//go:embed locales/*.json
+var files embed.FS
+
+// catalogs maps each language to its messages keyed by code.
+var catalogs = map[string]map[string]string{}
+
+// languages lists the supported languages, the default language first.
+var languages = []string{}
+
+func init() {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+
+		lang := strings.TrimSuffix(entry.Name(), ".json")
+		catalogs[lang] = catalog
+		languages = append(languages, lang)
+	}
+
+	if _, ok := catalogs[DefaultLanguage]; !ok {
+		panic("i18n: missing catalog of the default language " + DefaultLanguage)
+	}
+
+	// keep the default language first so it wins ties in Match
+	slices.SortFunc(languages, func(a, b string) int {
+		switch {
+		case a == DefaultLanguage:
+			return -1
+		case b == DefaultLanguage:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// Languages returns the supported languages, the default language first.
+func Languages() []string {
+	return slices.Clone(languages)
+}
+
+// Match returns the supported language the client prefers according to an
+// Accept-Language header such as "fr-CA, fr;q=0.9, en;q=0.8". Regional tags
+// match their base language. DefaultLanguage is returned when no supported
+// language is accepted.
+func Match(acceptLanguage string) string {
+	type accepted struct {
+		tag string
+		q   float64
+	}
+
+	ranges := []accepted{}
+	for item := range strings.SplitSeq(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(item, ";")
+
+		a := accepted{tag: strings.ToLower(strings.TrimSpace(tag)), q: 1}
+		for param := range strings.SplitSeq(params, ";") {
+			key, weight, ok := strings.Cut(param, "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(weight), 64); err == nil {
+					a.q = q
+				}
+			}
+		}
+
+		if a.tag != "" && a.q > 0 {
+			ranges = append(ranges, a)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, a := range ranges {
+		if a.tag == "*" {
+			return DefaultLanguage
+		}
+
+		base, _, _ := strings.Cut(a.tag, "-")
+		if _, ok := catalogs[base]; ok {
+			return base
+		}
+	}
+
+	return DefaultLanguage
+}
+
+// Lookup returns the message template of code in lang, falling back to the
+// default language. It reports false when no catalog defines code.
+func Lookup(lang string, code string) (string, bool) {
+	if message, ok := catalogs[lang][code]; ok {
+		return message, true
+	}
+
+	message, ok := catalogs[DefaultLanguage][code]
+	return message, ok
+}
+
+// Format replaces each {name} placeholder of message with the value of name in args.
+func Format(message string, args Args) string {
+	if len(args) == 0 {
+		return message
+	}
+
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// T returns the message of code in lang with args filled in. The code itself
+// is returned when no catalog defines it.
+func T(lang string, code string, args Args) string {
+	message, ok := Lookup(lang, code)
+	if !ok {
+		return code
+	}
+
+	return Format(message, args)
+}
+
+// contextKey is the type of the key the language is stored under in a context.
+type contextKey struct{}
+
+// WithLanguage returns a copy of ctx carrying lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language stored in ctx, or DefaultLanguage.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
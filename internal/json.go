@@ -3,12 +3,18 @@ package internal
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
 )
 
+// DefaultMaxBodySize is the largest request body ReadJSON reads, in bytes,
+// unless the route allows larger bodies with ContextSetMaxBodySize.
+const DefaultMaxBodySize = 1_048_576
+
 // WriteJSON writes the provided data as JSON to the http.ResponseWriter with the given
 // statusCode. It marshals the value, sets the Content-Type header and writes the response.
 // On marshal or write failure the error is logged via LogError.
@@ -32,17 +38,18 @@ func WriteJSON(
 	}
 }
 
-// ReadJSON decodes JSON from the provided http.Request into data. It enforces a 1MB
-// body limit, disallows unknown fields and returns detailed errors for malformed or
-// invalid request bodies.
+// ReadJSON decodes JSON from the provided http.Request into data. It enforces the
+// body limit of the route, 1MB by default, disallows unknown fields and returns
+// detailed errors for malformed or invalid request bodies, in the language of
+// the request.
 func ReadJSON(
 	w http.ResponseWriter,
 	r *http.Request,
 	data any,
 ) error {
-	// limit the maximum request body size to 1mb
-	maxSizeLimit := 1_048_576
-	r.Body = http.MaxBytesReader(w, r.Body, int64(maxSizeLimit))
+	// limit the maximum request body size to the limit of the route
+	maxSizeLimit := ContextGetMaxBodySize(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, maxSizeLimit)
 
 	// setup a new json decoder
 	dec := json.NewDecoder(r.Body)
@@ -53,41 +60,59 @@ func ReadJSON(
 	// read the data from the decoder
 	err := dec.Decode(data)
 
+	lang := i18n.FromContext(r.Context())
+
 	if err != nil {
 		var SyntaxError *json.SyntaxError
 		var UnmarshalTypeError *json.UnmarshalTypeError
 		var InvalidUnmarshalError *json.InvalidUnmarshalError
+		var MaxBytesError *http.MaxBytesError
 
 		switch {
 		case errors.As(err, &SyntaxError):
-			return fmt.Errorf("invalid request body. Syntax error on line %d", SyntaxError.Offset)
+			return errors.New(i18n.T(lang, "json.syntax", i18n.Args{"offset": SyntaxError.Offset}))
 		case errors.As(err, &UnmarshalTypeError):
 			if UnmarshalTypeError.Field != "" {
-				return fmt.Errorf("invalid Request body. The JSON object has invalid type %v", UnmarshalTypeError.Field)
+				return errors.New(i18n.T(lang, "json.invalid_type", i18n.Args{"field": UnmarshalTypeError.Field}))
 			} else {
-				return errors.New("invalid request body. Syntax Error")
+				return errors.New(i18n.T(lang, "json.malformed", nil))
 			}
 		case errors.Is(err, io.EOF):
-			return fmt.Errorf("invalid Request body: The request body cannot be empty")
+			return errors.New(i18n.T(lang, "json.empty", nil))
 		case errors.As(err, &InvalidUnmarshalError):
 			logger := NewLogger()
 			logger.Log.Panic().Err(err).Msg("internal Server error: Failed to read JSON body")
 
 		case errors.Is(err, io.ErrUnexpectedEOF):
-			return errors.New("invalid Request body: Sysntax error")
+			return errors.New(i18n.T(lang, "json.malformed", nil))
 
-		case strings.Contains(err.Error(), "too large"):
-			return errors.New("invalid request body: The request body cannot be larger than 1mb")
+		case errors.As(err, &MaxBytesError):
+			return errors.New(i18n.T(lang, "json.too_large", i18n.Args{"limit": formatSize(maxSizeLimit)}))
 		case strings.Contains(err.Error(), "unknown field"):
-			return fmt.Errorf("invalid request body: the request contains unknown field %v", strings.TrimPrefix(err.Error(), "json: unknown field "))
+			field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			return errors.New(i18n.T(lang, "json.unknown_field", i18n.Args{"field": field}))
+		default:
+			return errors.New(i18n.T(lang, "json.malformed", nil))
 		}
 	}
 
 	err = dec.Decode(&struct{}{})
 
 	if !errors.Is(err, io.EOF) {
-		return errors.New("invalid request body: request contain multiple JSON")
+		return errors.New(i18n.T(lang, "json.multiple_values", nil))
 	}
 
 	return nil
 }
+
+// formatSize formats a size in bytes in the largest unit dividing it, e.g. 1MB.
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return strconv.FormatInt(size>>20, 10) + "MB"
+	case size >= 1<<10 && size%(1<<10) == 0:
+		return strconv.FormatInt(size>>10, 10) + "KB"
+	default:
+		return strconv.FormatInt(size, 10) + "B"
+	}
+}
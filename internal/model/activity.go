@@ -0,0 +1,144 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// Activity types recorded in a group's feed.
+const (
+	ActivityGroupCreated            = "group_created"
+	ActivityGroupRenamed            = "group_renamed"
+	ActivityGroupCurrencyChanged    = "group_currency_changed"
+	ActivityGroupDescriptionChanged = "group_description_changed"
+	ActivityGroupDeleted            = "group_deleted"
+	ActivityMemberJoined            = "member_joined"
+)
+
+// Activity represents a single human-readable entry of a group's activity feed.
+type Activity struct {
+	Id        int64         `json:"id"`
+	GroupId   int           `json:"group_id"`
+	ActorId   *int          `json:"actor_id"`
+	Type      string        `json:"type"`
+	Message   string        `json:"message"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt string        `json:"created_at"`
+}
+
+// FieldChange describes a single field whose value changed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// ActivityQuery represents the cursor pagination parameters used when reading
+// a group's activity feed. Cursor is the id of the last activity already seen;
+// zero starts from the newest activity.
+type ActivityQuery struct {
+	GroupId int   `json:"group_id"`
+	Cursor  int64 `json:"cursor" validate:"min=0"`
+	Limit   int   `json:"limit" validate:"min=1,max=100"`
+}
+
+// ActivityMetaData holds the cursor and unread information returned with a feed page.
+type ActivityMetaData struct {
+	NextCursor  *int64 `json:"next_cursor"`
+	Limit       int    `json:"limit"`
+	LastReadId  int64  `json:"last_read_id"`
+	UnreadCount int    `json:"unread_count"`
+}
+
+// ActivityPage is a page of a group's activity feed with its cursor
+// information, as read for each query of a batch.
+type ActivityPage struct {
+	Activities []*Activity
+	Metadata   ActivityMetaData
+}
+
+// ActivityRead represents the JSON payload used to mark a feed as read.
+// A nil LastReadId marks every activity in the feed as read.
+type ActivityRead struct {
+	LastReadId *int64 `json:"last_read_id"`
+}
+
+// ValidateActivityQuery checks the ActivityQuery fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+func (input *ActivityQuery) ValidateActivityQuery(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
+
+// GroupActivities describes the transition of a group from before to after as
+// a list of typed activities. A nil before describes a creation and a nil after
+// a deletion; otherwise one activity is returned per changed field.
+func GroupActivities(before *Group, after *Group, actor *int) []*Activity {
+	who := "Someone"
+	if actor != nil {
+		who = fmt.Sprintf("User %d", *actor)
+	}
+
+	switch {
+	case before == nil && after != nil:
+		return []*Activity{{
+			GroupId: after.Id,
+			Type:    ActivityGroupCreated,
+			Message: fmt.Sprintf("%s created the group %q", who, after.Name),
+			Changes: []FieldChange{},
+		}}
+	case before != nil && after == nil:
+		return []*Activity{{
+			GroupId: before.Id,
+			Type:    ActivityGroupDeleted,
+			Message: fmt.Sprintf("%s deleted the group %q", who, before.Name),
+			Changes: []FieldChange{},
+		}}
+	case before == nil && after == nil:
+		return nil
+	}
+
+	activities := []*Activity{}
+
+	if before.Name != after.Name {
+		activities = append(activities, &Activity{
+			GroupId: after.Id,
+			Type:    ActivityGroupRenamed,
+			Message: fmt.Sprintf("%s renamed the group from %q to %q", who, before.Name, after.Name),
+			Changes: []FieldChange{{Field: "name", From: before.Name, To: after.Name}},
+		})
+	}
+
+	if before.Currency != after.Currency {
+		activities = append(activities, &Activity{
+			GroupId: after.Id,
+			Type:    ActivityGroupCurrencyChanged,
+			Message: fmt.Sprintf("%s changed the currency from %s to %s", who, before.Currency, after.Currency),
+			Changes: []FieldChange{{Field: "currency", From: before.Currency, To: after.Currency}},
+		})
+	}
+
+	if before.Description != after.Description {
+		activities = append(activities, &Activity{
+			GroupId: after.Id,
+			Type:    ActivityGroupDescriptionChanged,
+			Message: fmt.Sprintf("%s updated the group description", who),
+			Changes: []FieldChange{{Field: "description", From: before.Description, To: after.Description}},
+		})
+	}
+
+	return activities
+}
+
+// MemberJoinedActivity describes the member joining its group.
+func MemberJoinedActivity(member *Member) *Activity {
+	return &Activity{
+		GroupId: member.GroupId,
+		Type:    ActivityMemberJoined,
+		Message: fmt.Sprintf("User %d joined the group as %s", member.UserId, member.Role),
+		Changes: []FieldChange{},
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// Audit actions recorded for every mutation.
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Audit entity types. Members have no id of their own, so their events are
+// recorded under the id of their group.
+const (
+	AuditEntityGroup  = "group"
+	AuditEntityMember = "member"
+)
+
+// AuditEvent represents a single recorded mutation of an entity. Before and
+// After hold the fields the change touched with their old and new values: all
+// fields of the new entity on create, all fields of the old one on delete.
+type AuditEvent struct {
+	Id         int64           `json:"id"`
+	ActorId    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityId   int             `json:"entity_id"`
+	RequestId  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// AuditQuery represents the filters created from request query parameters
+// when listing audit events.
+type AuditQuery struct {
+	ActorId    int       `json:"actor_id" validate:"min=0"`
+	EntityType string    `json:"entity_type" validate:"omitempty,oneof=group member"`
+	EntityId   int       `json:"entity_id" validate:"min=0"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Page       int       `json:"page" validate:"min=1,max=10000000"`
+	PageSize   int       `json:"page_size" validate:"min=1,max=100"`
+}
+
+// ValidateAuditQuery checks the AuditQuery fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+func (input *AuditQuery) ValidateAuditQuery(val *validation.Validator) map[string]string {
+	val.CheckCode(
+		input.From.IsZero() || input.To.IsZero() || !input.To.Before(input.From),
+		"to",
+		"validation.not_before",
+		i18n.Args{"field": "to", "other": "from"},
+	)
+
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
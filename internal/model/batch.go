@@ -0,0 +1,99 @@
+package model
+
+import (
+	"slices"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// Operations of a group batch.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// GroupBatch represents the JSON payload of a batch of group operations. An
+// atomic batch applies every operation or none; otherwise each operation is
+// applied on its own and reported separately.
+type GroupBatch struct {
+	Atomic     bool                  `json:"atomic"`
+	Operations []GroupBatchOperation `json:"operations" validate:"required,min=1,max=1000"`
+}
+
+// GroupBatchOperation is a single operation of a GroupBatch: create creates
+// Group, update replaces the group identified by Id with Group, and delete
+// deletes the group identified by Id.
+type GroupBatchOperation struct {
+	Op    string      `json:"op" validate:"required,oneof=create update delete"`
+	Id    int         `json:"id,omitempty"`
+	Group *GroupInput `json:"group,omitempty" validate:"-"`
+}
+
+// GroupBatchResult reports the outcome of an operation of a GroupBatch with
+// the status and body its own request would have been answered with. Error
+// holds a message, or the messages of the invalid fields.
+type GroupBatchResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status int    `json:"status"`
+	Group  *Group `json:"group,omitempty"`
+	Error  any    `json:"error,omitempty"`
+}
+
+// GroupBatchResponse represents the JSON response to a GroupBatch, with the
+// result of each operation in the order of the batch.
+type GroupBatchResponse struct {
+	Atomic    bool               `json:"atomic"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []GroupBatchResult `json:"results"`
+}
+
+// ValidateGroupBatch checks the GroupBatch fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+// The groups of the operations are checked separately, by Validate.
+func (input *GroupBatch) ValidateGroupBatch(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
+
+// NewGroup returns the group the operation creates or updates.
+func (input *GroupBatchOperation) NewGroup() *Group {
+	group := &Group{Id: input.Id}
+	if input.Group != nil {
+		group.Name = input.Group.Name
+		group.Currency = input.Group.Currency
+		group.Description = input.Group.Description
+		group.CreatedBy = input.Group.CreatedBy
+	}
+	return group
+}
+
+// Validate checks a single operation using the provided validation.Validator:
+// the id of updates and deletes, and the group of creates and updates with
+// Group.Validate. It returns a map of field -> error message when validation
+// fails, or nil when valid.
+func (input *GroupBatchOperation) Validate(val *validation.Validator) map[string]string {
+	if slices.Contains([]string{BatchUpdate, BatchDelete}, input.Op) {
+		val.CheckCode(input.Id > 0, "id", "validation.required", i18n.Args{"field": "id"})
+	}
+
+	if slices.Contains([]string{BatchCreate, BatchUpdate}, input.Op) {
+		if input.Group == nil {
+			val.AddCode("group", "validation.required", i18n.Args{"field": "group"})
+		} else if errs := input.NewGroup().Validate(validation.NewLocalized(val.Lang)); errs != nil {
+			for field, message := range errs {
+				val.Add("group."+field, message)
+			}
+		}
+	}
+
+	if !val.Valid() {
+		return val.Errors
+	}
+	return nil
+}
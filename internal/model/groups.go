@@ -1,17 +1,19 @@
 package model
 
 import (
-	"fmt"
-	"strings"
+	"errors"
+	"maps"
 
+	"github.com/Abdul4code/FairShare/internal/filter"
+	"github.com/Abdul4code/FairShare/internal/i18n"
 	"github.com/Abdul4code/FairShare/internal/validation"
 )
 
 // GroupOutput represents a group object returned to API clients.
 type Group struct {
 	Id          int    `json:"id"`
-	Name        string `json:"name"`
-	Currency    string `json:"currency"`
+	Name        string `json:"name" validate:"required,min=2,max=255"`
+	Currency    string `json:"currency" validate:"required,oneof_currency"`
 	Description string `json:"description"`
 	CreatedBy   int    `json:"created_by"`
 	CreatedAt   string `json:"created_at"`
@@ -19,87 +21,80 @@ type Group struct {
 }
 
 // GroupInput represents the JSON payload used when creating a group.
+// Its validate tags mirror the rules of Group, which the input is checked
+// against, so they appear in the API documentation.
 type GroupInput struct {
-	Name        string `json:"name"`
-	Currency    string `json:"currency"`
+	Name        string `json:"name" validate:"required,min=2,max=255"`
+	Currency    string `json:"currency" validate:"required,oneof_currency"`
 	Description string `json:"description"`
 	CreatedBy   int    `json:"created_by"`
 }
 
 // GroupUpdate represents the JSON payload used when updating a group.
+// Its validate tags mirror the rules of Group, like those of GroupInput.
 type GroupUpdate struct {
-	Name        *string `json:"name"`
-	Currency    *string `json:"currency"`
+	Name        *string `json:"name" validate:"omitempty,min=2,max=255"`
+	Currency    *string `json:"currency" validate:"omitempty,oneof_currency"`
 	Description *string `json:"description"`
 	CreatedBy   *int    `json:"created_by"`
 }
 
-// GroupQuery represents the JSON item created from request query parameters
+// GroupQuery represents the JSON item created from request query parameters.
+// Filter holds an expression over GroupFilterFields, parsed into Where by
+// ValidateGroupQuery. Sort lists the fields to sort by in order, each
+// optionally prefixed with - for descending order; groups are finally sorted
+// by id.
 type GroupQuery struct {
-	Name        string `json:"name"`
-	Currency    string `json:"currency"`
-	Description string `json:"description"`
-	Page        int    `json:"page"`
-	PageSize    int    `json:"page_size"`
-	Sort        string `json:"sort"`
+	Name        string      `json:"name"`
+	Currency    []string    `json:"currency" validate:"dive,oneof_currency"`
+	Description string      `json:"description"`
+	Filter      string      `json:"filter" validate:"max=2000"`
+	Page        int         `json:"page" validate:"min=1,max=10000000"`
+	PageSize    int         `json:"page_size" validate:"min=1,max=100"`
+	Sort        []string    `json:"sort" validate:"max=4,dive,sort=name currency created_at id"`
+	Offset      int         `json:"-"` // rows skipped when set, replacing Page for cursor pagination
+	Where       filter.Expr `json:"-"`
 }
 
-// ValidateGroupQuery checks the GroupQuery fields using the provided validation.Validator.
-// It returns a map of field -> error message when validation fails, or nil when valid.
-func (input *GroupQuery) ValidateGroupQuery(val *validation.Validator) map[string]string {
-	supportedCurrency := []string{"Dollar", "Euro", "Pound", "Naira"}
-	supportedSortFields := []string{"name", "currency", "created_at", "id"}
-
-	// check that the currency is among the list of supported currencies
-	val.Check(
-		val.In(input.Currency, supportedCurrency) || input.Currency == "",
-		"currency",
-		fmt.Sprintf("Unsurported Currency. It should be one of %v", supportedCurrency),
-	)
-
-	// check that page is a value between 1 to 10,000,000
-	val.Check(
-		input.Page >= 1 && input.Page <= 10_000_000,
-		"page",
-		"unsurported Page Value. Value should be between 1 and 10,000,000",
-	)
-
-	// check that limit is a value between 1 and 100
-	val.Check(
-		input.PageSize >= 1 && input.PageSize <= 100,
-		"page_size",
-		"unsurported page size value: Value should be between 1 and 100",
-	)
-
-	// check that the sort is in the possible values to sort by
-	sort := strings.TrimSuffix(input.Sort, "-")
-	sort = strings.TrimSuffix(sort, "+")
-
-	val.Check(
-		val.In(sort, supportedSortFields) || input.Sort == "",
-		"sort",
-		fmt.Sprintf("Unsurported Sort values. It should be one of %v", supportedSortFields),
-	)
+// GroupFilterFields lists the fields the filter of a GroupQuery may compare.
+var GroupFilterFields = filter.Fields{
+	"id":          {Column: "id", Type: filter.Int},
+	"name":        {Column: "name", Type: filter.String},
+	"currency":    {Column: "currency", Type: filter.String, Values: SupportedCurrencies},
+	"description": {Column: "description", Type: filter.String},
+	"created_by":  {Column: "created_by", Type: filter.Int},
+	"created_at":  {Column: "created_at", Type: filter.Time},
+	"version":     {Column: "version", Type: filter.Int},
+}
 
-	if ok := val.Valid(); !ok {
+// ValidateGroupQuery checks the GroupQuery fields using the provided validation.Validator,
+// and parses Filter into Where. It returns a map of field -> error message when
+// validation fails, or nil when valid.
+func (input *GroupQuery) ValidateGroupQuery(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
 		return val.Errors
 	}
+
+	if input.Filter != "" {
+		where, err := filter.Parse(input.Filter, GroupFilterFields)
+
+		var filterErr *filter.Error
+		if errors.As(err, &filterErr) {
+			args := i18n.Args{"field": "filter"}
+			maps.Copy(args, filterErr.Args)
+			val.AddCode("filter", filterErr.Code, args)
+			return val.Errors
+		}
+		input.Where = where
+	}
+
 	return nil
 }
 
 // Validate checks the Group fields using the provided validation.Validator.
 // It returns a map of field -> error message when validation fails, or nil when valid.
 func (input *Group) Validate(val *validation.Validator) map[string]string {
-	supportedCurrency := []string{"Dollar", "Euro", "Pound", "Naira"}
-
-	val.Check(len(input.Name) > 1, "Name", "The Name of the group cannot be empty")
-	val.Check(
-		val.In(input.Currency, supportedCurrency),
-		"Currency",
-		fmt.Sprintf("Unsurported Currency. It should be one of %v", supportedCurrency),
-	)
-
-	if ok := val.Valid(); !ok {
+	if ok := val.Struct(input); !ok {
 		return val.Errors
 	}
 	return nil
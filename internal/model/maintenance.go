@@ -0,0 +1,28 @@
+package model
+
+// Kinds of orphaned rows found by the maintenance checks.
+const (
+	OrphanGroupWithoutOwner = "group_without_owner" // no member owns the group
+	OrphanReadWithoutGroup  = "read_without_group"  // an activity read marker of a deleted group
+)
+
+// Orphan describes a row left inconsistent with the rest of the data.
+type Orphan struct {
+	Kind    string `json:"kind"`
+	Table   string `json:"table"`
+	Key     string `json:"key"`     // primary key of the row, e.g. 12 or 12/7
+	Details string `json:"details"` // what is wrong with the row
+}
+
+// GroupExport holds everything stored about a group. Group is nil once the
+// group is deleted, while its history and activities remain.
+type GroupExport struct {
+	Group       *Group        `json:"group"`
+	Members     []*Member     `json:"members"`
+	Activities  []*Activity   `json:"activities"`
+	AuditEvents []*AuditEvent `json:"audit_events"`
+	ExportedAt  string        `json:"exported_at"`
+}
+
+// VacuumResult counts the rows removed by a vacuum, per table.
+type VacuumResult map[string]int64
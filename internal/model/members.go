@@ -0,0 +1,15 @@
+package model
+
+// Group member roles.
+const (
+	MemberRoleOwner  = "owner"
+	MemberRoleMember = "member"
+)
+
+// Member represents the membership of a user in a group.
+type Member struct {
+	GroupId  int    `json:"group_id"`
+	UserId   int    `json:"user_id"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
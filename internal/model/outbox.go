@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// OutboxMessage represents an event written to the outbox in the same
+// transaction as the change it describes, waiting to be published.
+type OutboxMessage struct {
+	Id            int64           `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   int             `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// EventPayload is the body of a published group event, shared by every sink
+// and delivered as-is to webhook receivers.
+type EventPayload struct {
+	Type      string    `json:"type"`
+	CreatedAt string    `json:"created_at"`
+	Data      *Activity `json:"data"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal/netguard"
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// SupportedCurrencies lists the currencies a group can use.
+var SupportedCurrencies = []string{"Dollar", "Euro", "Pound", "Naira"}
+
+// register the validation rules specific to the models
+func init() {
+	validation.Register(
+		"oneof_currency",
+		oneOfString(SupportedCurrencies),
+		fmt.Sprintf("Unsupported currency. It should be one of %v", SupportedCurrencies),
+	)
+
+	validation.Register(
+		"oneof_event_type",
+		oneOfString(WebhookEventTypes),
+		fmt.Sprintf("Unsupported event type. It should be one of %v", WebhookEventTypes),
+	)
+
+	validation.Register(
+		"public_url",
+		publicURL,
+		"URL must not point to localhost or a private, loopback or link-local address",
+	)
+
+	validation.Register(
+		"sort",
+		sortField,
+		"Unsupported sort value. It should be one of [{param}], optionally prefixed with - for descending order",
+	)
+}
+
+// oneOfString returns a rule checking a string is one of values.
+func oneOfString(values []string) validation.RuleFunc {
+	return func(value reflect.Value, param string) bool {
+		return value.Kind() == reflect.String && slices.Contains(values, value.String())
+	}
+}
+
+// publicURL checks the host of a URL is not localhost or a non-public IP
+// address. Host names are resolved and checked again when the URL is dialed.
+func publicURL(value reflect.Value, param string) bool {
+	if value.Kind() != reflect.String {
+		return false
+	}
+
+	u, err := url.Parse(value.String())
+	return err == nil && netguard.IsPublicHost(u.Hostname())
+}
+
+// sortField checks a sort key names one of the space separated fields of
+// param, optionally prefixed with - (descending) or + (ascending). The same
+// signs are accepted as a suffix, as in earlier versions of the API.
+func sortField(value reflect.Value, param string) bool {
+	if value.Kind() != reflect.String {
+		return false
+	}
+
+	field := strings.TrimLeft(value.String(), "+-")
+	if field == value.String() {
+		field = strings.TrimRight(field, "+-")
+	}
+	return slices.Contains(strings.Fields(param), field)
+}
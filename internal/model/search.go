@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// SearchQuery represents the query parameters of a group search. Q holds the
+// words to search for, in the web search syntax of Postgres: "quoted phrases",
+// or between alternatives and - before excluded words.
+type SearchQuery struct {
+	Q        string `json:"q" validate:"required,max=200"`
+	Page     int    `json:"page" validate:"min=1,max=10000000"`
+	PageSize int    `json:"page_size" validate:"min=1,max=100"`
+}
+
+// SearchResult is a group matching a search, with its relevance and its name
+// and description with the matching words highlighted.
+type SearchResult struct {
+	Group     Group           `json:"group"`
+	Rank      float64         `json:"rank"`
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+// SearchHighlight holds the name and an excerpt of the description of a group
+// with the words matching a search wrapped in <mark> and </mark>, as HTML: the
+// text of the group is escaped.
+type SearchHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// searchConfigs maps the languages of the API to the text search config
+// stemming their words. The search_vector column of groups holds the words of
+// every config listed here.
+var searchConfigs = map[string]string{
+	"en": "english",
+	"fr": "french",
+}
+
+// SearchConfig returns the text search config of lang, or simple, which does
+// not stem words, for languages without one.
+func SearchConfig(lang string) string {
+	if config, ok := searchConfigs[lang]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// ValidateSearchQuery checks the SearchQuery fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+func (input *SearchQuery) ValidateSearchQuery(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
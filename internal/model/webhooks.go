@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/Abdul4code/FairShare/internal/validation"
+)
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookEventTypes lists the event types a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	ActivityGroupCreated,
+	ActivityGroupRenamed,
+	ActivityGroupCurrencyChanged,
+	ActivityGroupDescriptionChanged,
+	ActivityGroupDeleted,
+	ActivityMemberJoined,
+}
+
+// Webhook represents a subscription of an external receiver to group events.
+// The secret is only returned to clients when the webhook is created.
+type Webhook struct {
+	Id         int      `json:"id"`
+	URL        string   `json:"url" validate:"url,public_url"`
+	Secret     string   `json:"secret,omitempty" validate:"min=16,max=128"`
+	EventTypes []string `json:"event_types" validate:"dive,oneof_event_type"`
+	Active     bool     `json:"active"`
+	CreatedBy  *int     `json:"created_by"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// WebhookInput represents the JSON payload used when creating a webhook.
+// A secret is generated when none is given.
+// Its validate tags mirror the rules of Webhook, which the input is checked
+// against, so they appear in the API documentation.
+type WebhookInput struct {
+	URL        string   `json:"url" validate:"required,url,public_url"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=128"`
+	EventTypes []string `json:"event_types" validate:"dive,oneof_event_type"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookUpdate represents the JSON payload used when updating a webhook.
+// Its validate tags mirror the rules of Webhook, like those of WebhookInput.
+type WebhookUpdate struct {
+	URL        *string   `json:"url" validate:"omitempty,url,public_url"`
+	Secret     *string   `json:"secret" validate:"omitempty,min=16,max=128"`
+	EventTypes *[]string `json:"event_types" validate:"omitempty,dive,oneof_event_type"`
+	Active     *bool     `json:"active"`
+}
+
+// WebhookDelivery represents a single event queued for delivery to a webhook,
+// along with the outcome of its latest attempt.
+type WebhookDelivery struct {
+	Id             int64           `json:"id"`
+	WebhookId      int             `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    *string         `json:"delivered_at"`
+}
+
+// WebhookQuery represents the pagination parameters created from request
+// query parameters when listing webhooks or deliveries. CreatedBy restricts
+// webhooks to those created by the user, unless zero.
+type WebhookQuery struct {
+	WebhookId int    `json:"webhook_id"`
+	CreatedBy int    `json:"-"`
+	Status    string `json:"status" validate:"omitempty,oneof=pending delivered failed"`
+	Page      int    `json:"page" validate:"min=1,max=10000000"`
+	PageSize  int    `json:"page_size" validate:"min=1,max=100"`
+}
+
+// Validate checks the Webhook fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+func (input *Webhook) Validate(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
+
+// ValidateWebhookQuery checks the WebhookQuery fields using the provided validation.Validator.
+// It returns a map of field -> error message when validation fails, or nil when valid.
+func (input *WebhookQuery) ValidateWebhookQuery(val *validation.Validator) map[string]string {
+	if ok := val.Struct(input); !ok {
+		return val.Errors
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the offer the client prefers according to header, a list
+// of values with optional q weights as sent in Accept or Accept-Encoding
+// (e.g. "text/csv, application/json;q=0.5"). Media ranges such as text/* and
+// */* and the * wildcard match any offer they cover, with the most specific
+// range deciding the weight of an offer. Offers of equal weight are preferred
+// in the order given. An empty header accepts the first offer; "" is returned
+// when the client accepts none of the offers.
+func Negotiate(header string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	type accepted struct {
+		value string
+		q     float64
+	}
+
+	ranges := []accepted{}
+	for item := range strings.SplitSeq(header, ",") {
+		value, params, _ := strings.Cut(item, ";")
+
+		a := accepted{value: strings.ToLower(strings.TrimSpace(value)), q: 1}
+		for param := range strings.SplitSeq(params, ";") {
+			key, weight, ok := strings.Cut(param, "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(weight), 64); err == nil {
+					a.q = q
+				}
+			}
+		}
+
+		if a.value != "" {
+			ranges = append(ranges, a)
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		specificity, q := -1, 0.0
+
+		for _, a := range ranges {
+			s := matchSpecificity(a.value, strings.ToLower(offer))
+			if s > specificity {
+				specificity, q = s, a.q
+			}
+		}
+
+		if specificity >= 0 && q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// matchSpecificity reports how specifically the accepted value a matches offer:
+// 2 for an exact match, 1 for a type/* range, 0 for */* or *, and -1 when a
+// does not match.
+func matchSpecificity(a string, offer string) int {
+	switch {
+	case a == offer:
+		return 2
+	case a == "*" || a == "*/*":
+		return 0
+	case strings.HasSuffix(a, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(a, "*")):
+		return 1
+	}
+
+	return -1
+}
@@ -0,0 +1,78 @@
+// Package netguard keeps the outgoing requests the server makes to addresses
+// chosen by users, such as webhook deliveries, away from the network the
+// server runs in: loopback, private, link-local (including cloud metadata
+// endpoints) and other non-public addresses are refused.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// ErrNonPublicAddress is returned when dialing an address that is not public.
+var ErrNonPublicAddress = errors.New("netguard: address is not public")
+
+// reserved lists the ranges that are global unicast by the netip
+// classification but never reachable on the public internet.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use IPv4/IPv6 translation
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("fec0::/10"),       // deprecated site-local
+	netip.MustParsePrefix("::ffff:0:0:0/96"), // IPv4-translated
+}
+
+// IsPublic reports whether addr is a public unicast address.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	return !slices.ContainsFunc(reserved, func(prefix netip.Prefix) bool { return prefix.Contains(addr) })
+}
+
+// IsPublicHost reports whether host, the host name or address of a URL, may
+// be public: host names other than localhost are accepted, as the addresses
+// they resolve to can change and are checked when dialing by Control.
+func IsPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	addr, err := netip.ParseAddr(strings.Trim(host, "[]"))
+	if err != nil {
+		return true
+	}
+
+	return IsPublic(addr)
+}
+
+// Control is a net.Dialer Control function refusing to connect to addresses
+// that are not public. It runs once the host name has been resolved, for every
+// connection, so names resolving to internal addresses and redirects to them
+// are refused as well.
+func Control(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublic(addr) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+	}
+
+	return nil
+}
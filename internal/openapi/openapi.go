@@ -0,0 +1,384 @@
+// Package openapi builds an OpenAPI 3.1 document from Go types. Struct schemas
+// are derived by reflection from their json tags, and their validate tags are
+// turned into the matching schema constraints.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version is the version of the OpenAPI specification documents follow.
+const Version = "3.1.0"
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	// Security lists the alternative requirements operations must satisfy;
+	// an empty requirement allows anonymous requests.
+	Security []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path, keyed by lower case HTTP method.
+type PathItem map[string]*Operation
+
+// Components holds the named schemas referenced from the document.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how requests authenticate.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement maps the names of security schemes to the scopes they
+// require.
+type SecurityRequirement map[string][]string
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationId string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes the body of a given content type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema is a JSON Schema as used by OpenAPI 3.1.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // a type name, or a list of them for nullable values
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+}
+
+// RuleFunc applies the constraint of a validation rule with the given
+// parameter to the schema of a field.
+type RuleFunc func(schema *Schema, param string)
+
+var (
+	describersMu sync.RWMutex
+	describers   = map[string]RuleFunc{}
+)
+
+// DescribeRule registers how the custom validation rule name constrains the
+// schema of the fields using it. Rules without a description are left out of
+// the schemas.
+func DescribeRule(name string, fn RuleFunc) {
+	describersMu.Lock()
+	defer describersMu.Unlock()
+
+	describers[name] = fn
+}
+
+// Generator collects the schemas of the types used by a document.
+type Generator struct {
+	schemas map[string]*Schema
+}
+
+// NewGenerator returns an empty Generator.
+func NewGenerator() *Generator {
+	return &Generator{schemas: map[string]*Schema{}}
+}
+
+// Schemas returns the named schemas generated so far, for the components of a document.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// Schema returns the schema of the type of v. Named struct types are added to
+// the component schemas and referenced; anonymous structs are inlined.
+func (g *Generator) Schema(v any) *Schema {
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	rawType  = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaOf returns the schema of the type t.
+func (g *Generator) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := g.schemaOf(t.Elem())
+		if name, ok := schema.Type.(string); ok {
+			schema.Type = []string{name, "null"}
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		if _, ok := g.schemas[t.Name()]; !ok {
+			g.schemas[t.Name()] = &Schema{} // placeholder for recursive types
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+
+	// interfaces accept any value
+	return &Schema{}
+}
+
+// structSchema returns the object schema of the struct type t.
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for _, field := range fields(t) {
+		property := g.schemaOf(field.Type)
+		if applyRules(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, jsonName(field))
+		}
+		schema.Properties[jsonName(field)] = property
+	}
+
+	return schema
+}
+
+// Parameters returns a parameter located in, e.g. "query", for each field of
+// the struct v, or for the fields with the given JSON names only.
+func (g *Generator) Parameters(in string, v any, names ...string) []Parameter {
+	params := []Parameter{}
+
+	for _, field := range fields(reflect.TypeOf(v)) {
+		name := jsonName(field)
+		if len(names) > 0 && !slices.Contains(names, name) {
+			continue
+		}
+
+		schema := g.schemaOf(field.Type)
+		required := applyRules(schema, field.Tag.Get("validate"))
+
+		param := Parameter{Name: name, In: in, Required: required, Schema: schema}
+
+		// lists are read from a single comma separated value, e.g. currency=Euro,Pound
+		if schema.Type == "array" {
+			explode := false
+			param.Explode = &explode
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// fields returns the exported fields of the struct type t serialized to JSON.
+func fields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	fields := []reflect.StructField{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.IsExported() && jsonName(field) != "-" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// jsonName returns the JSON name of a struct field.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// applyRules adds the constraints of the rules of a validate tag to schema,
+// applying rules after dive to its items. It reports whether the field is required.
+func applyRules(schema *Schema, tag string) bool {
+	required := false
+	target := schema
+
+	for part := range strings.SplitSeq(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch name {
+		case "":
+		case "required":
+			required = target == schema
+		case "dive":
+			if schema.Items != nil {
+				target = schema.Items
+			}
+		case "min", "max", "len":
+			applyBound(target, name, param)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				target.Enum = append(target.Enum, enumValue(target, value))
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "unique":
+			target.UniqueItems = true
+		default:
+			describersMu.RLock()
+			fn, ok := describers[name]
+			describersMu.RUnlock()
+
+			if ok {
+				fn(target, param)
+			}
+		}
+	}
+
+	return required
+}
+
+// applyBound applies a min, max or len rule to schema according to its type.
+func applyBound(schema *Schema, rule string, param string) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	n := int(limit)
+	lower, upper := rule == "min" || rule == "len", rule == "max" || rule == "len"
+
+	switch baseType(schema) {
+	case "string":
+		if lower {
+			schema.MinLength = &n
+		}
+		if upper {
+			schema.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &n
+		}
+		if upper {
+			schema.MaxItems = &n
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &limit
+		}
+		if upper {
+			schema.Maximum = &limit
+		}
+	}
+}
+
+// enumValue converts an enum value written in a tag to the type of schema.
+func enumValue(schema *Schema, value string) any {
+	switch baseType(schema) {
+	case "integer":
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// baseType returns the type of schema without null.
+func baseType(schema *Schema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// Dispatcher periodically claims unpublished outbox messages and publishes each
+// to every sink, retrying failed messages with exponential backoff.
+type Dispatcher struct {
+	Outbox      repository.OutboxModel
+	Sinks       []Sink
+	Interval    time.Duration // time between polls when the outbox is drained
+	BatchSize   int           // maximum messages claimed per poll
+	BaseBackoff time.Duration // delay before the first retry, doubled per attempt
+	MaxBackoff  time.Duration // upper bound of the delay between attempts
+}
+
+// NewDispatcher returns a Dispatcher publishing to sinks with the default
+// polling and retry settings.
+func NewDispatcher(outbox repository.OutboxModel, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		Outbox:      outbox,
+		Sinks:       sinks,
+		Interval:    time.Second,
+		BatchSize:   100,
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  10 * time.Minute,
+	}
+}
+
+// Run publishes messages until ctx is cancelled. While full batches are being
+// published the outbox is polled again immediately.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		published, err := d.Outbox.Process(ctx, d.BatchSize, d.publish, d.backoff)
+		if err != nil && ctx.Err() == nil {
+			internal.NewLogger().Log.Error().Err(err).Msg("failed to process outbox")
+		}
+
+		if err == nil && published == d.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.Interval):
+		}
+	}
+}
+
+// publish hands msg to every sink, stopping at the first failure.
+func (d *Dispatcher) publish(ctx context.Context, msg *model.OutboxMessage) error {
+	for _, sink := range d.Sinks {
+		if err := sink.Publish(ctx, msg); err != nil {
+			return fmt.Errorf("%s sink: %w", sink.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt after the given number of
+// failed attempts: BaseBackoff doubled per attempt and capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempts && delay < d.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.MaxBackoff)
+}
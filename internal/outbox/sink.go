@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// Sink receives published outbox messages. A message is only marked published
+// once every sink accepted it, and is retried otherwise, so sinks must tolerate
+// receiving the same message more than once.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, msg *model.OutboxMessage) error
+}
+
+// Bus is an in-process sink that hands every message to the registered handlers.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []func(ctx context.Context, msg *model.OutboxMessage)
+}
+
+// NewBus creates and returns a Bus without handlers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler called synchronously for every published message.
+// Handlers must be quick and must not block.
+func (b *Bus) Subscribe(handler func(ctx context.Context, msg *model.OutboxMessage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Name implements Sink.
+func (b *Bus) Name() string {
+	return "bus"
+}
+
+// Publish implements Sink by calling every handler with msg.
+func (b *Bus) Publish(ctx context.Context, msg *model.OutboxMessage) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(ctx, msg)
+	}
+
+	return nil
+}
+
+// WebhookSink queues a delivery of every message to the subscribed webhooks.
+type WebhookSink struct {
+	Webhooks repository.WebhookModel
+}
+
+// Name implements Sink.
+func (s WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Publish implements Sink. Deliveries are unique per message and webhook, so
+// publishing a message again does not deliver it twice.
+func (s WebhookSink) Publish(ctx context.Context, msg *model.OutboxMessage) error {
+	return s.Webhooks.EnqueueDeliveries(ctx, msg)
+}
+
+// FileSink appends every message as a JSON line to a file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens, creating it when missing, the file at path for appending.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{file: f}, nil
+}
+
+// Name implements Sink.
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+// Publish implements Sink. The line is synced to disk before returning.
+func (s *FileSink) Publish(ctx context.Context, msg *model.OutboxMessage) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bucket is the state of a token bucket held in memory.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryStore keeps token buckets in the memory of the process. Limits only
+// hold per instance; use PostgresStore when the API runs on several instances.
+type MemoryStore struct {
+	Idle time.Duration // time after which an unused bucket is removed
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Idle:    10 * time.Minute,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Take takes one token from the bucket identified by key.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return newResult(limit, b.tokens, allowed), nil
+}
+
+// Run removes buckets unused for longer than s.Idle until ctx is cancelled.
+func (s *MemoryStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cutoff := time.Now().Add(-s.Idle)
+
+		s.mu.Lock()
+		for key, b := range s.buckets {
+			if b.updated.Before(cutoff) {
+				delete(s.buckets, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
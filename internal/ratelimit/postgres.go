@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// PostgresStore keeps token buckets in the rate_limit_buckets table so limits
+// hold across every instance of the API sharing the database.
+type PostgresStore struct {
+	Buckets repository.RateLimitModel
+	Idle    time.Duration // time after which an unused bucket is removed
+}
+
+// NewPostgresStore returns a PostgresStore keeping buckets through the given model.
+func NewPostgresStore(buckets repository.RateLimitModel) *PostgresStore {
+	return &PostgresStore{
+		Buckets: buckets,
+		Idle:    time.Hour,
+	}
+}
+
+// Take takes one token from the bucket identified by key.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	tokens, allowed, err := s.Buckets.Take(ctx, key, limit.Rate, limit.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(limit, tokens, allowed), nil
+}
+
+// Run removes buckets unused for longer than s.Idle until ctx is cancelled.
+func (s *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Buckets.DeleteIdle(ctx, s.Idle); err != nil && ctx.Err() == nil {
+			internal.NewLogger().Log.Error().Err(err).Msg("failed to remove idle rate limit buckets")
+		}
+	}
+}
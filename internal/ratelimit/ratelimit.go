@@ -0,0 +1,133 @@
+// Package ratelimit implements token bucket rate limiting with buckets kept in
+// memory or in Postgres.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket: it holds up to Burst tokens and refills at
+// Rate tokens per second. Every request takes one token. The zero Limit
+// disables limiting.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit restricts requests.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Window returns the time an empty bucket takes to refill completely.
+func (l Limit) Window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Policy holds the limits of a route group: Anonymous applies per client IP
+// and User per authenticated user.
+type Policy struct {
+	Anonymous Limit
+	User      Limit
+}
+
+// ParsePolicy parses a policy written as "anon=RATE:BURST,user=RATE:BURST",
+// e.g. "anon=5:10,user=20:40". A client kind left out is not limited.
+func ParsePolicy(spec string) (Policy, error) {
+	policy := Policy{}
+
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kind, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Policy{}, fmt.Errorf("rate limit %q must have the form kind=rate:burst", part)
+		}
+
+		limit, err := parseLimit(value)
+		if err != nil {
+			return Policy{}, fmt.Errorf("rate limit %q: %w", part, err)
+		}
+
+		switch strings.TrimSpace(kind) {
+		case "anon":
+			policy.Anonymous = limit
+		case "user":
+			policy.User = limit
+		default:
+			return Policy{}, fmt.Errorf("rate limit %q: unknown client kind %q, expected anon or user", part, kind)
+		}
+	}
+
+	return policy, nil
+}
+
+// parseLimit parses a limit written as "RATE:BURST".
+func parseLimit(value string) (Limit, error) {
+	rate, burst, ok := strings.Cut(value, ":")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit must have the form rate:burst")
+	}
+
+	r, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+	if err != nil || r < 0 {
+		return Limit{}, fmt.Errorf("rate must be a non-negative number")
+	}
+
+	b, err := strconv.Atoi(strings.TrimSpace(burst))
+	if err != nil || b < 0 {
+		return Limit{}, fmt.Errorf("burst must be a non-negative integer")
+	}
+
+	return Limit{Rate: r, Burst: b}, nil
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int           // size of the bucket
+	Remaining  int           // whole tokens left
+	Reset      time.Duration // time until the bucket is full again
+	RetryAfter time.Duration // time until the next token is available, zero when allowed
+}
+
+// newResult describes a bucket of limit left with tokens after a request.
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	tokens = max(tokens, 0)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return result
+}
+
+// seconds converts a number of seconds to a duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(max(s, 0) * float64(time.Second))
+}
+
+// Store keeps token buckets.
+type Store interface {
+	// Take takes one token from the bucket identified by key, creating a full
+	// bucket of limit when it does not exist.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+
+	// Run removes idle buckets until ctx is cancelled.
+	Run(ctx context.Context)
+}
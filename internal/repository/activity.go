@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/events"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/lib/pq"
+)
+
+// ActivityModel provides database operations for the activities and
+// activity_reads tables. It holds a reference to a connection pool or transaction.
+type ActivityModel struct {
+	conn DBTX
+}
+
+// recordGroupActivities writes the feed entries describing the transition of a
+// group from before to after inside the given transaction, see recordActivities.
+func recordGroupActivities(ctx context.Context, tx DBTX, before *model.Group, after *model.Group) error {
+	var actor *int
+	if id, ok := internal.ContextGetActor(ctx); ok {
+		actor = &id
+	}
+
+	return recordActivities(ctx, tx, model.GroupActivities(before, after, actor))
+}
+
+// recordActivities writes the feed entries of a single group inside the given
+// transaction. Each entry is also written to the outbox for publication, and a
+// notification on events.NotifyChannel is queued that Postgres delivers on
+// commit.
+func recordActivities(ctx context.Context, tx DBTX, activities []*model.Activity) error {
+	var actor *int
+	if id, ok := internal.ContextGetActor(ctx); ok {
+		actor = &id
+	}
+
+	query := `INSERT INTO activities (group_id, actor_id, type, message, changes)
+				VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id, created_at;
+			`
+
+	for _, activity := range activities {
+		changes, err := json.Marshal(activity.Changes)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(
+			ctx,
+			query,
+			activity.GroupId,
+			actor,
+			activity.Type,
+			activity.Message,
+			changes,
+		).Scan(&activity.Id, &activity.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		activity.ActorId = actor
+		if err := enqueueGroupEvent(ctx, tx, activity); err != nil {
+			return err
+		}
+	}
+
+	if len(activities) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`SELECT pg_notify($1, $2)`,
+		events.NotifyChannel,
+		strconv.Itoa(activities[0].GroupId),
+	)
+
+	return err
+}
+
+// GetFeed retrieves a page of a group's activity feed, newest first. Activities
+// older than filters.Cursor are returned; a zero cursor starts from the newest.
+//
+// When reader is non-zero, the returned metadata includes the reader's last read
+// marker and the number of activities newer than it.
+func (m ActivityModel) GetFeed(ctx context.Context, filters *model.ActivityQuery, reader int) ([]*model.Activity, model.ActivityMetaData, error) {
+	metadata := model.ActivityMetaData{Limit: filters.Limit}
+
+	query := `
+		SELECT id, group_id, actor_id, type, message, changes, created_at
+		FROM activities
+		WHERE group_id = $1 AND (id < $2 OR $2 = 0)
+		ORDER BY id DESC
+		LIMIT $3;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// fetch one extra row to learn whether another page exists
+	rows, err := m.conn.QueryContext(ctx, query, filters.GroupId, filters.Cursor, filters.Limit+1)
+	if err != nil {
+		return nil, model.ActivityMetaData{}, err
+	}
+	defer rows.Close()
+
+	activities, err := scanActivities(rows)
+	if err != nil {
+		return nil, model.ActivityMetaData{}, err
+	}
+
+	if len(activities) > filters.Limit {
+		activities = activities[:filters.Limit]
+		next := activities[len(activities)-1].Id
+		metadata.NextCursor = &next
+	}
+
+	if reader == 0 {
+		return activities, metadata, nil
+	}
+
+	query = `
+		SELECT marker, (SELECT count(id) FROM activities WHERE group_id = $1 AND id > marker)
+		FROM (
+			SELECT COALESCE(
+				(SELECT last_read_id FROM activity_reads WHERE group_id = $1 AND user_id = $2), 0
+			) AS marker
+		) AS reads;
+		`
+
+	err = m.conn.QueryRowContext(ctx, query, filters.GroupId, reader).Scan(
+		&metadata.LastReadId,
+		&metadata.UnreadCount,
+	)
+	if err != nil {
+		return nil, model.ActivityMetaData{}, err
+	}
+
+	return activities, metadata, nil
+}
+
+// GetFeeds retrieves the feed pages of several queries in one round trip, as
+// GetFeed does for each of them without a reader, so the activities of every
+// group of a list are read in a single query. Pages are keyed by their query.
+func (m ActivityModel) GetFeeds(ctx context.Context, queries []model.ActivityQuery) (map[model.ActivityQuery]*model.ActivityPage, error) {
+	groupIds := make([]int64, len(queries))
+	cursors := make([]int64, len(queries))
+	limits := make([]int64, len(queries))
+	for i, q := range queries {
+		groupIds[i], cursors[i], limits[i] = int64(q.GroupId), q.Cursor, int64(q.Limit)
+	}
+
+	// each query reads one extra row to learn whether another page exists
+	query := `
+		SELECT q.n, a.id, a.group_id, a.actor_id, a.type, a.message, a.changes, a.created_at
+		FROM unnest($1::int[], $2::bigint[], $3::int[]) WITH ORDINALITY AS q(group_id, cursor, lim, n)
+		CROSS JOIN LATERAL (
+			SELECT id, group_id, actor_id, type, message, changes, created_at
+			FROM activities
+			WHERE group_id = q.group_id AND (id < q.cursor OR q.cursor = 0)
+			ORDER BY id DESC
+			LIMIT q.lim + 1
+		) AS a
+		ORDER BY q.n, a.id DESC;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx, query, pq.Array(groupIds), pq.Array(cursors), pq.Array(limits))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pages := make(map[model.ActivityQuery]*model.ActivityPage, len(queries))
+	for _, q := range queries {
+		pages[q] = &model.ActivityPage{Activities: []*model.Activity{}, Metadata: model.ActivityMetaData{Limit: q.Limit}}
+	}
+
+	for rows.Next() {
+		var n int
+		activity, err := scanActivity(rows, &n)
+		if err != nil {
+			return nil, err
+		}
+
+		q := queries[n-1]
+		page := pages[q]
+		if len(page.Activities) == q.Limit {
+			next := page.Activities[q.Limit-1].Id
+			page.Metadata.NextCursor = &next
+			continue
+		}
+		page.Activities = append(page.Activities, activity)
+	}
+
+	return pages, rows.Err()
+}
+
+// MarkRead moves the reader's "unread since" marker of a group's feed to
+// lastReadId. A nil lastReadId marks every activity as read. The marker never
+// moves backwards. It returns the stored marker.
+func (m ActivityModel) MarkRead(ctx context.Context, groupId int, reader int, lastReadId *int64) (int64, error) {
+	query := `
+		INSERT INTO activity_reads (group_id, user_id, last_read_id)
+		VALUES ($1, $2, COALESCE($3, (SELECT COALESCE(max(id), 0) FROM activities WHERE group_id = $1)))
+		ON CONFLICT (group_id, user_id) DO UPDATE
+			SET last_read_id = GREATEST(activity_reads.last_read_id, EXCLUDED.last_read_id),
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING last_read_id;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	marker := sql.NullInt64{}
+	if lastReadId != nil {
+		marker = sql.NullInt64{Int64: *lastReadId, Valid: true}
+	}
+
+	var stored int64
+	err := m.conn.QueryRowContext(ctx, query, groupId, reader, marker).Scan(&stored)
+
+	return stored, err
+}
+
+// GetSince retrieves up to limit activities of a group created after the
+// activity with id afterId, oldest first. It is used to stream new activities
+// and to resume a stream from the last activity a client received.
+func (m ActivityModel) GetSince(ctx context.Context, groupId int, afterId int64, limit int) ([]*model.Activity, error) {
+	query := `
+		SELECT id, group_id, actor_id, type, message, changes, created_at
+		FROM activities
+		WHERE group_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3;
+		`
+
+	rows, err := m.conn.QueryContext(ctx, query, groupId, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanActivities(rows)
+}
+
+// LatestId returns the id of the newest activity of a group, or zero when the
+// group has no activity.
+func (m ActivityModel) LatestId(ctx context.Context, groupId int) (int64, error) {
+	query := `SELECT COALESCE(max(id), 0) FROM activities WHERE group_id = $1`
+
+	var id int64
+	err := m.conn.QueryRowContext(ctx, query, groupId).Scan(&id)
+
+	return id, err
+}
+
+// scanActivities reads every row selected as
+// id, group_id, actor_id, type, message, changes, created_at.
+func scanActivities(rows *sql.Rows) ([]*model.Activity, error) {
+	activities := []*model.Activity{}
+
+	for rows.Next() {
+		activity, err := scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, rows.Err()
+}
+
+// scanActivity reads the current row, selected as the columns of before
+// followed by id, group_id, actor_id, type, message, changes, created_at.
+func scanActivity(rows *sql.Rows, before ...any) (*model.Activity, error) {
+	activity := model.Activity{}
+	actor := sql.NullInt64{}
+	var changes []byte
+
+	dest := append(before,
+		&activity.Id,
+		&activity.GroupId,
+		&actor,
+		&activity.Type,
+		&activity.Message,
+		&changes,
+		&activity.CreatedAt,
+	)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if actor.Valid {
+		id := int(actor.Int64)
+		activity.ActorId = &id
+	}
+
+	if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+		return nil, err
+	}
+
+	return &activity, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// AuditModel provides database operations for the audit_events table.
+// It holds a reference to a connection pool or transaction.
+type AuditModel struct {
+	conn DBTX
+}
+
+// recordAudit writes an audit event using tx, normally the transaction of the
+// change it describes so both are committed or rolled back together.
+//
+// before and after are the states of the entity around the change; pass nil
+// for a missing side (before on create, after on delete). Only the fields the
+// change touched are stored, see auditDiff. The actor and request id are read
+// from ctx.
+func recordAudit(
+	ctx context.Context,
+	tx DBTX,
+	action string,
+	entityType string,
+	entityId int,
+	before any,
+	after any,
+) error {
+	beforeJSON, afterJSON, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor := sql.NullInt64{}
+	if id, ok := internal.ContextGetActor(ctx); ok {
+		actor = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	query := `INSERT INTO audit_events (actor_id, action, entity_type, entity_id, request_id, before, after)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		actor,
+		action,
+		entityType,
+		entityId,
+		internal.ContextGetRequestID(ctx),
+		beforeJSON,
+		afterJSON,
+	)
+
+	return err
+}
+
+// auditDiff marshals before and after to JSON objects holding only the fields
+// whose values differ between them, so an update records the fields it
+// changed with their old and new values. A nil side is returned as nil, so
+// the column is stored as NULL, and the other side is kept whole: a create
+// records every field of the new entity and a delete every field of the old.
+func auditDiff(before any, after any) ([]byte, []byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for name, value := range beforeFields {
+			if other, ok := afterFields[name]; ok && bytes.Equal(value, other) {
+				delete(beforeFields, name)
+				delete(afterFields, name)
+			}
+		}
+	}
+
+	beforeJSON, err := auditJSON(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	afterJSON, err := auditJSON(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return beforeJSON, afterJSON, nil
+}
+
+// auditFields marshals value and splits the resulting object into its fields,
+// returning nil for a nil value.
+func auditFields(value any) (map[string]json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// auditJSON marshals fields back to a JSON object, returning nil for nil
+// fields.
+func auditJSON(fields map[string]json.RawMessage) ([]byte, error) {
+	if fields == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(fields)
+}
+
+// GetAll retrieves audit events from the database, newest first. It supports
+// filtering by actor, entity and a created_at time range, as well as pagination.
+//
+// It returns a slice of pointers to model.AuditEvent, a model.MetaData struct
+// containing pagination info, and an error if any occurred during the query.
+func (m AuditModel) GetAll(ctx context.Context, filters *model.AuditQuery) ([]*model.AuditEvent, model.MetaData, error) {
+	events := []*model.AuditEvent{}
+	metadata := model.MetaData{}
+
+	query := `
+		SELECT count(id) OVER(), id, actor_id, action, entity_type, entity_id, request_id, before, after, created_at
+		FROM audit_events
+		WHERE
+			(actor_id = $1 OR $1 = 0)
+		AND
+			(entity_type = $2 OR $2 = '')
+		AND
+			(entity_id = $3 OR $3 = 0)
+		AND
+			(created_at >= $4 OR $4 IS NULL)
+		AND
+			(created_at <= $5 OR $5 IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6 OFFSET $7;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx,
+		query,
+		filters.ActorId,
+		filters.EntityType,
+		filters.EntityId,
+		nullTime(filters.From),
+		nullTime(filters.To),
+		filters.PageSize,
+		(filters.Page-1)*filters.PageSize,
+	)
+	if err != nil {
+		return nil, model.MetaData{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event := model.AuditEvent{}
+		actor := sql.NullInt64{}
+		var before, after []byte
+
+		err := rows.Scan(
+			&metadata.Total,
+			&event.Id,
+			&actor,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityId,
+			&event.RequestId,
+			&before,
+			&after,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, model.MetaData{}, err
+		}
+
+		if actor.Valid {
+			id := int(actor.Int64)
+			event.ActorId = &id
+		}
+		event.Before = before
+		event.After = after
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, model.MetaData{}, err
+	}
+
+	metadata.CurrentPage = filters.Page
+	metadata.LastPage = int(math.Ceil(float64(metadata.Total) / float64(filters.PageSize)))
+	metadata.PageSize = filters.PageSize
+
+	return events, metadata, nil
+}
+
+// nullTime converts a zero time into a NULL query argument.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/Abdul4code/FairShare/internal"
 	_ "github.com/lib/pq"
 )
 
@@ -16,10 +18,31 @@ type DB_Config struct {
 	MaxIdleConTime string // Maximum idle connection time
 }
 
+// DBTX is implemented by both *sql.DB and *sql.Tx, so repository functions
+// accepting it run either directly on the pool or inside a caller's transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // Models is a wrapper struct that holds instances of all the model structs contained within the application.
 // model structs holds database operations for a specific table.
+//
+// Models returned by NewModels run on the connection pool; the Models passed to
+// a WithTx callback are bound to that transaction.
 type Models struct {
-	Groups GroupModel
+	db *sql.DB // connection pool transactions are started on
+
+	Groups      GroupModel
+	Members     MemberModel
+	Audit       AuditModel
+	Activities  ActivityModel
+	Webhooks    WebhookModel
+	Outbox      OutboxModel
+	Health      HealthModel
+	RateLimits  RateLimitModel
+	Maintenance MaintenanceModel
 }
 
 // New creates a new database connection pool and returns it.
@@ -54,7 +77,57 @@ func New(cfg DB_Config) (*sql.DB, error) {
 
 // NewModels returns a Models struct containing instances of the model structs.
 func NewModels(db *sql.DB) *Models {
+	models := newModels(db)
+	models.db = db
+
+	return models
+}
+
+// newModels returns a Models struct whose model structs run on conn. Every
+// statement is traced.
+func newModels(conn DBTX) *Models {
+	conn = traced(conn)
+
 	return &Models{
-		Groups: GroupModel{db},
+		Groups:      GroupModel{conn},
+		Members:     MemberModel{conn},
+		Audit:       AuditModel{conn},
+		Activities:  ActivityModel{conn},
+		Webhooks:    WebhookModel{conn},
+		Outbox:      OutboxModel{conn},
+		Health:      HealthModel{conn},
+		RateLimits:  RateLimitModel{conn},
+		Maintenance: MaintenanceModel{conn},
+	}
+}
+
+// ConfigFromEnv reads the connection pool settings from the dsn, db_max_cons,
+// db_max_idle_cons and db_max_idle_time environment variables.
+func ConfigFromEnv() (DB_Config, error) {
+	dsn, err := internal.GetString("dsn")
+	if err != nil {
+		return DB_Config{}, err
 	}
+
+	maxCon, err := internal.GetEnvInt("db_max_cons")
+	if err != nil {
+		return DB_Config{}, fmt.Errorf("db_max_cons: %w", err)
+	}
+
+	maxIdleCon, err := internal.GetEnvInt("db_max_idle_cons")
+	if err != nil {
+		return DB_Config{}, fmt.Errorf("db_max_idle_cons: %w", err)
+	}
+
+	maxIdleTime, err := internal.GetString("db_max_idle_time")
+	if err != nil {
+		return DB_Config{}, err
+	}
+
+	return DB_Config{
+		DSN:            dsn,
+		MaxCon:         maxCon,
+		MaxIdleCon:     maxIdleCon,
+		MaxIdleConTime: maxIdleTime,
+	}, nil
 }
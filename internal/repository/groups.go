@@ -9,41 +9,56 @@ import (
 	"time"
 
 	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/filter"
 	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/lib/pq"
 )
 
 // GroupModel provides database operations for the groups table.
-// It holds a reference to a sql.DB connection pool.
+// It holds a reference to a connection pool or transaction.
 type GroupModel struct {
-	conn *sql.DB
+	conn DBTX
 }
 
 // Insert inserts a new group row into the database and populates
 // the given model.Group with the returned id and created_at timestamp.
 //
+// The insert and its audit, activity and outbox rows are written in a single
+// transaction, joining the caller's transaction when m is bound to one.
 // The function expects the caller to have validated fields on data.
 // It returns any error encountered while executing the query or scanning
 // the returned row.
-func (m GroupModel) Insert(data *model.Group) error {
+func (m GroupModel) Insert(ctx context.Context, data *model.Group) error {
 	query := `INSERT INTO groups (name, currency, description, created_by)
 				VALUES ($1, $2, $3, $4)
-			  RETURNING id, created_at;
+			  RETURNING id, created_at, version;
 			`
 
-	// QueryRow is used because exactly one row is expected to be returned.
-	row := m.conn.QueryRow(
-		query,
-		data.Name,
-		data.Currency,
-		data.Description,
-		data.CreatedBy,
-	)
+	return inTx(ctx, m.conn, func(tx DBTX) error {
+		// QueryRow is used because exactly one row is expected to be returned.
+		row := tx.QueryRowContext(
+			ctx,
+			query,
+			data.Name,
+			data.Currency,
+			data.Description,
+			data.CreatedBy,
+		)
 
-	// Scan the returned id and created_at into the provided struct.
-	// Note: if the schema changes, the RETURNING list must be kept in sync.
-	err := row.Scan(&data.Id, &data.CreatedAt)
+		// Scan the returned id and created_at into the provided struct.
+		// Note: if the schema changes, the RETURNING list must be kept in sync.
+		err := row.Scan(&data.Id, &data.CreatedAt, &data.Version)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, tx, model.AuditActionCreate, model.AuditEntityGroup, data.Id, nil, data)
+		if err != nil {
+			return err
+		}
 
-	return err
+		return recordGroupActivities(ctx, tx, nil, data)
+	})
 }
 
 // Get retrieves a group by its integer id. If the id is invalid (<1)
@@ -52,16 +67,17 @@ func (m GroupModel) Insert(data *model.Group) error {
 // The function returns a pointer to model.Group on success. It returns
 // internal.ErrNotFound when no row is found so callers can distinguish
 // "not found" from other errors.
-func (m GroupModel) Get(id int) (*model.Group, error) {
+func (m GroupModel) Get(ctx context.Context, id int) (*model.Group, error) {
 	if id < 1 {
 		return nil, internal.ErrNotFound
 	}
 
-	// Avoid SELECT * in production code because column order matters for Scan.
-	// Using explicit column names is safer and clearer.
-	query := `SELECT * FROM groups WHERE id = $1;
+	// Avoid SELECT * in production code because column order matters for Scan,
+	// and the table has columns, such as search_vector, that are not scanned.
+	query := `SELECT id, name, currency, description, created_by, created_at, version
+			  FROM groups WHERE id = $1;
 			`
-	row := m.conn.QueryRow(query, id)
+	row := m.conn.QueryRowContext(ctx, query, id)
 
 	group := &model.Group{}
 	err := row.Scan(
@@ -91,122 +107,234 @@ func (m GroupModel) Get(id int) (*model.Group, error) {
 // via the version column. It expects data.Id and data.Version to be set
 // to target the correct row/version.
 //
-// If the id is invalid, ErrNotFound is returned. If the WHERE clause
-// matches no rows (concurrent update or missing row), ErrNotFound is returned.
-func (m GroupModel) Update(data *model.Group) error {
-	// Debug print preserved from original code; remove in production if noisy.
-	fmt.Println(data)
-
+// The previous state of the row is locked and read first so the audit event,
+// written in the same transaction, can record the fields that changed.
+//
+// If the id is invalid or the row is missing, ErrNotFound is returned. If the
+// row's version differs from data.Version, ErrEditConflict is returned.
+func (m GroupModel) Update(ctx context.Context, data *model.Group) error {
 	if data.Id < 1 {
 		return internal.ErrNotFound
 	}
 
-	// Note: RETURNING * will return all columns; the Scan call below MUST match
-	// the column order returned by the database. Prefer explicit RETURNING column list
-	// to avoid subtle bugs if schema changes.
-	query := `UPDATE groups
-				SET name = $1,
-				currency = $2,
-				description = $3,
-				version = version + 1
-			  WHERE id = $4 AND version=$5
-			  RETURNING *
-			`
-	row := m.conn.QueryRow(
-		query,
-		data.Name,
-		data.Currency,
-		data.Description,
-		data.Id,
-		data.Version,
-	)
+	return inTx(ctx, m.conn, func(tx DBTX) error {
+		before, err := getGroupForUpdate(ctx, tx, data.Id)
+		if err != nil {
+			return err
+		}
 
-	// Scan updated row back into the model. Order here must match the DB's column order.
-	err := row.Scan(
-		&data.Id,
-		&data.Name,
-		&data.Currency,
-		&data.Description,
-		&data.CreatedBy,
-		&data.CreatedAt,
-		&data.Version,
-	)
+		// the row is locked, so a version mismatch means an earlier update won.
+		if before.Version != data.Version {
+			return internal.ErrEditConflict
+		}
 
-	// If no rows were returned, treat as not found (concurrent edit or deleted).
-	if err != nil {
-		fmt.Println(err)
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return internal.ErrNotFound
-		default:
+		// Note: the RETURNING column list must match the Scan call below.
+		query := `UPDATE groups
+					SET name = $1,
+					currency = $2,
+					description = $3,
+					version = version + 1
+				  WHERE id = $4 AND version=$5
+				  RETURNING id, name, currency, description, created_by, created_at, version
+				`
+		row := tx.QueryRowContext(
+			ctx,
+			query,
+			data.Name,
+			data.Currency,
+			data.Description,
+			data.Id,
+			data.Version,
+		)
+
+		// Scan updated row back into the model. Order here must match the RETURNING list.
+		err = row.Scan(
+			&data.Id,
+			&data.Name,
+			&data.Currency,
+			&data.Description,
+			&data.CreatedBy,
+			&data.CreatedAt,
+			&data.Version,
+		)
+
+		// If no rows were returned, treat as not found (concurrent edit or deleted).
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return internal.ErrNotFound
+			default:
+				return err
+			}
+		}
+
+		err = recordAudit(ctx, tx, model.AuditActionUpdate, model.AuditEntityGroup, data.Id, before, data)
+		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		return recordGroupActivities(ctx, tx, before, data)
+	})
 }
 
 // DeleteGroup deletes a group by id. It returns ErrNotFound when the id
 // is invalid or when no rows were affected by the delete operation.
 //
-// Uses Exec instead of QueryRow because no rows are expected to be returned.
-func (m *GroupModel) DeleteGroup(id int) error {
+// The deleted row is locked and read first so the audit event, written in the
+// same transaction, can record its last state.
+func (m *GroupModel) DeleteGroup(ctx context.Context, id int) error {
 	if id < 1 {
 		return internal.ErrNotFound
 	}
 
-	query := `DELETE FROM groups WHERE id = $1`
-	res, err := m.conn.Exec(query, id)
-	if err != nil {
-		return err
+	return inTx(ctx, m.conn, func(tx DBTX) error {
+		before, err := getGroupForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, tx, model.AuditActionDelete, model.AuditEntityGroup, id, before, nil)
+		if err != nil {
+			return err
+		}
+
+		// record the deletion while the members, deleted with the group, still
+		// exist, so that their webhooks are told about it
+		if err := recordGroupActivities(ctx, tx, before, nil); err != nil {
+			return err
+		}
+
+		query := `DELETE FROM groups WHERE id = $1`
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		// RowsAffected tells us whether the delete actually removed a row.
+		affectedRows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affectedRows == 0 {
+			return internal.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// GetForUpdate retrieves a group by its integer id and locks its row until the
+// transaction ends, so that it cannot change before the caller writes it. Call
+// it on models bound to a transaction, see Models.WithTx; outside of one the
+// lock is released as soon as the row is read.
+//
+// It returns internal.ErrNotFound when the id is invalid (<1) or no row matches.
+func (m GroupModel) GetForUpdate(ctx context.Context, id int) (*model.Group, error) {
+	if id < 1 {
+		return nil, internal.ErrNotFound
 	}
 
-	// RowsAffected tells us whether the delete actually removed a row.
-	affectedRows, err := res.RowsAffected()
+	return getGroupForUpdate(ctx, m.conn, id)
+}
+
+// getGroupForUpdate reads a group inside tx and locks its row until the
+// transaction ends. It returns internal.ErrNotFound when no row matches.
+func getGroupForUpdate(ctx context.Context, tx DBTX, id int) (*model.Group, error) {
+	query := `SELECT id, name, currency, description, created_by, created_at, version
+			  FROM groups WHERE id = $1
+			  FOR UPDATE;
+			`
+
+	group := &model.Group{}
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&group.Id,
+		&group.Name,
+		&group.Currency,
+		&group.Description,
+		&group.CreatedBy,
+		&group.CreatedAt,
+		&group.Version,
+	)
+
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, internal.ErrNotFound
+		default:
+			return nil, err
+		}
 	}
 
-	if affectedRows == 0 {
-		return internal.ErrNotFound
+	return group, nil
+}
+
+// groupSortColumns maps the fields groups can be sorted by to their column.
+var groupSortColumns = map[string]string{
+	"name":       "name",
+	"currency":   "currency",
+	"created_at": "created_at",
+	"id":         "id",
+}
+
+// groupConditions returns the WHERE conditions selecting the groups matching
+// filters, and their arguments. The filter expression, when there is one, is
+// compiled with its values passed as arguments after those of the other filters.
+func groupConditions(filters *model.GroupQuery) (string, []any) {
+	currencies := filters.Currency
+	if currencies == nil {
+		currencies = []string{}
+	}
+
+	where := `
+			(name ILIKE '%' || $1 || '%' OR $1 = '')
+		AND 
+			(currency = ANY($2) OR cardinality($2::text[]) = 0)
+		AND 
+			(to_tsvector('simple', description) @@ plainto_tsquery('simple', $3) OR $3 = '')`
+	args := []any{filters.Name, pq.Array(currencies), filters.Description}
+
+	if filters.Where != nil {
+		condition, filterArgs := filter.SQL(filters.Where, len(args)+1)
+		where += `
+		AND 
+			` + condition
+		args = append(args, filterArgs...)
 	}
 
-	return nil
+	return where, args
 }
 
 // GetAll retrieves a list of groups from the database. It supports filtering
-// by name, currency, and description, as well as pagination and sorting.
+// by name, currencies, description and a filter expression, as well as
+// pagination and sorting.
 //
 // It returns a slice of pointers to model.Group, a model.MetaData struct
 // containing pagination info, and an error if any occurred during the query.
-func (m GroupModel) GetAll(filters *model.GroupQuery) ([]*model.Group, model.MetaData, error) {
+func (m GroupModel) GetAll(ctx context.Context, filters *model.GroupQuery) ([]*model.Group, model.MetaData, error) {
 	groups := []*model.Group{}
 	metadata := model.MetaData{}
+
+	offset := (filters.Page - 1) * filters.PageSize
+	if filters.Offset > 0 {
+		offset = filters.Offset
+	}
+	where, args := groupConditions(filters)
 	query := fmt.Sprintf(`
 		SELECT count(id) OVER(), id, name, currency, description, created_by, created_at, version
 		FROM groups
-		WHERE 
-			(name ILIKE '%%' || $1 || '%%' OR $1 = '')
-		AND 
-			(currency = $2 OR $2 = '')
-		AND 
-			(to_tsvector('simple', description) @@ plainto_tsquery('simple', $3) OR $3 = '')
-		ORDER BY %s %s, id ASC
+		WHERE %s
+		ORDER BY %s
 		LIMIT %d OFFSET %d;
-		`, internal.GetSortValue(filters.Sort), internal.GetSortDirection(filters.Sort),
+		`, where, orderBy(filters.Sort, groupSortColumns),
 		filters.PageSize,
-		(filters.Page-1)*filters.PageSize,
+		offset,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	row, err := m.conn.QueryContext(ctx,
-		query,
-		filters.Name,
-		filters.Currency,
-		filters.Description,
-	)
+	row, err := m.conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, model.MetaData{}, err
 	}
@@ -238,3 +366,53 @@ func (m GroupModel) GetAll(filters *model.GroupQuery) ([]*model.Group, model.Met
 
 	return groups, metadata, nil
 }
+
+// exportTimeout bounds the time a streamed export of groups may take.
+const exportTimeout = 5 * time.Minute
+
+// Stream retrieves every group matching filters, in the requested sort order,
+// and hands them to fn one at a time as they are read from the rows cursor, so
+// large exports are never held in memory. Pagination is ignored. Streaming
+// stops at the first error returned by fn.
+func (m GroupModel) Stream(ctx context.Context, filters *model.GroupQuery, fn func(*model.Group) error) error {
+	where, args := groupConditions(filters)
+	query := fmt.Sprintf(`
+		SELECT id, name, currency, description, created_by, created_at, version
+		FROM groups
+		WHERE %s
+		ORDER BY %s;
+		`, where, orderBy(filters.Sort, groupSortColumns),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		group := model.Group{}
+
+		err := rows.Scan(
+			&group.Id,
+			&group.Name,
+			&group.Currency,
+			&group.Description,
+			&group.CreatedBy,
+			&group.CreatedAt,
+			&group.Version,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(&group); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// HealthModel provides the database checks used by the readiness probe.
+// It holds a reference to a connection pool or transaction.
+type HealthModel struct {
+	conn DBTX
+}
+
+// Ping runs a trivial query to check the database accepts and answers queries.
+func (m HealthModel) Ping(ctx context.Context) error {
+	var one int
+	return m.conn.QueryRowContext(ctx, `SELECT 1`).Scan(&one)
+}
+
+// SchemaVersion returns the version recorded by the migration tool in the
+// schema_migrations table and whether the last migration failed half way.
+// A database without migrations reports version zero.
+func (m HealthModel) SchemaVersion(ctx context.Context) (int, bool, error) {
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+	var version int
+	var dirty bool
+
+	err := m.conn.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// MaintenanceModel provides the data repair and cleanup operations used by
+// operators. It holds a reference to a connection pool or transaction.
+type MaintenanceModel struct {
+	conn DBTX
+}
+
+// maintenanceTimeout bounds the statements scanning or cleaning whole tables.
+const maintenanceTimeout = 5 * time.Minute
+
+// FindOrphans lists the rows left inconsistent with the rest of the data: groups
+// without any owner, and activity read markers of deleted groups.
+func (m MaintenanceModel) FindOrphans(ctx context.Context) ([]*model.Orphan, error) {
+	query := `
+		SELECT $1, 'groups', g.id::text, 'no owner, created_by ' || g.created_by
+		FROM groups g
+		WHERE NOT EXISTS (
+			SELECT 1 FROM group_members gm
+			WHERE gm.group_id = g.id AND gm.role = 'owner'
+		)
+		UNION ALL
+		SELECT $2, 'activity_reads', r.group_id || '/' || r.user_id, 'group ' || r.group_id || ' does not exist'
+		FROM activity_reads r
+		WHERE NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = r.group_id)
+		ORDER BY 1, 3;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, maintenanceTimeout)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx, query, model.OrphanGroupWithoutOwner, model.OrphanReadWithoutGroup)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orphans := []*model.Orphan{}
+	for rows.Next() {
+		orphan := model.Orphan{}
+		if err := rows.Scan(&orphan.Kind, &orphan.Table, &orphan.Key, &orphan.Details); err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, &orphan)
+	}
+
+	return orphans, rows.Err()
+}
+
+// RepairOrphans fixes the rows reported by FindOrphans in a single transaction:
+// the creator of each group without any owner becomes its owner, and read
+// markers of deleted groups are removed. Groups that already have an owner are
+// left alone, even when it is not their creator, as are groups without a valid
+// creator (created_by <= 0), which need an owner chosen by hand. It returns the
+// number of rows changed per kind.
+func (m MaintenanceModel) RepairOrphans(ctx context.Context) (map[string]int64, error) {
+	repaired := map[string]int64{}
+
+	ctx, cancel := context.WithTimeout(ctx, maintenanceTimeout)
+	defer cancel()
+
+	err := inTx(ctx, m.conn, func(tx DBTX) error {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO group_members (group_id, user_id, role)
+			SELECT g.id, g.created_by, 'owner' FROM groups g
+			WHERE g.created_by > 0 AND NOT EXISTS (
+				SELECT 1 FROM group_members gm
+				WHERE gm.group_id = g.id AND gm.role = 'owner'
+			)
+			ON CONFLICT (group_id, user_id) DO UPDATE SET role = 'owner';
+			`)
+		if err != nil {
+			return err
+		}
+		if repaired[model.OrphanGroupWithoutOwner], err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		res, err = tx.ExecContext(ctx, `
+			DELETE FROM activity_reads r
+			WHERE NOT EXISTS (SELECT 1 FROM groups g WHERE g.id = r.group_id);
+			`)
+		if err != nil {
+			return err
+		}
+		repaired[model.OrphanReadWithoutGroup], err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repaired, nil
+}
+
+// Vacuum removes data kept only for a while: rate limit buckets idle for an
+// hour, which are full again, and outbox messages and webhook deliveries that
+// were settled more than retention ago. The audit log and activities are kept.
+func (m MaintenanceModel) Vacuum(ctx context.Context, retention time.Duration) (model.VacuumResult, error) {
+	statements := []struct {
+		table string
+		query string
+		args  []any
+	}{
+		{
+			"rate_limit_buckets",
+			`DELETE FROM rate_limit_buckets WHERE updated_at < now() - interval '1 hour'`,
+			nil,
+		},
+		{
+			"outbox",
+			`DELETE FROM outbox
+			 WHERE published_at IS NOT NULL AND published_at < CURRENT_TIMESTAMP - make_interval(secs => $1)`,
+			[]any{retention.Seconds()},
+		},
+		{
+			"webhook_deliveries",
+			`DELETE FROM webhook_deliveries
+			 WHERE status <> 'pending' AND created_at < CURRENT_TIMESTAMP - make_interval(secs => $1)`,
+			[]any{retention.Seconds()},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maintenanceTimeout)
+	defer cancel()
+
+	result := model.VacuumResult{}
+	for _, statement := range statements {
+		res, err := m.conn.ExecContext(ctx, statement.query, statement.args...)
+		if err != nil {
+			return result, err
+		}
+
+		if result[statement.table], err = res.RowsAffected(); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
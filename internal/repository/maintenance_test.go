@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRepairOrphansOnlyRepairsGroupsWithoutOwner(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// groups owned by someone other than their creator, and groups without a
+	// valid creator, are not given an owner
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE g.created_by > 0 AND NOT EXISTS (
+				SELECT 1 FROM group_members gm
+				WHERE gm.group_id = g.id AND gm.role = 'owner'
+			)`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`DELETE FROM activity_reads`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repaired, err := NewModels(db).Maintenance.RepairOrphans(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if repaired[model.OrphanGroupWithoutOwner] != 2 || repaired[model.OrphanReadWithoutGroup] != 1 {
+		t.Errorf("repaired %v, want 2 groups and 1 read marker", repaired)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/lib/pq"
+)
+
+// MemberModel provides database operations for the group_members table.
+// It holds a reference to a connection pool or transaction.
+type MemberModel struct {
+	conn DBTX
+}
+
+// Insert adds a user to a group and populates the given model.Member with the
+// returned joined_at timestamp. The audit event and the "member joined"
+// activity are recorded in the same transaction.
+func (m MemberModel) Insert(ctx context.Context, data *model.Member) error {
+	query := `INSERT INTO group_members (group_id, user_id, role)
+				VALUES ($1, $2, $3)
+			  RETURNING joined_at;
+			`
+
+	return inTx(ctx, m.conn, func(tx DBTX) error {
+		err := tx.QueryRowContext(ctx, query, data.GroupId, data.UserId, data.Role).Scan(&data.JoinedAt)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, tx, model.AuditActionCreate, model.AuditEntityMember, data.GroupId, nil, data)
+		if err != nil {
+			return err
+		}
+
+		return recordActivities(ctx, tx, []*model.Activity{model.MemberJoinedActivity(data)})
+	})
+}
+
+// IsMember reports whether the user belongs to the group identified by groupId.
+func (m MemberModel) IsMember(ctx context.Context, groupId int, userId int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM group_members WHERE group_id = $1 AND user_id = $2)`
+
+	var member bool
+	err := m.conn.QueryRowContext(ctx, query, groupId, userId).Scan(&member)
+
+	return member, err
+}
+
+// GetAll retrieves the members of the group identified by groupId, owners first.
+func (m MemberModel) GetAll(ctx context.Context, groupId int) ([]*model.Member, error) {
+	query := `SELECT group_id, user_id, role, joined_at
+			  FROM group_members
+			  WHERE group_id = $1
+			  ORDER BY role = 'owner' DESC, joined_at, user_id;
+			`
+
+	rows, err := m.conn.QueryContext(ctx, query, groupId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*model.Member{}
+	for rows.Next() {
+		member := model.Member{}
+		if err := rows.Scan(&member.GroupId, &member.UserId, &member.Role, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+// GetByGroups retrieves the members of each group identified by groupIds in a
+// single query, keyed by group id, owners first. Groups without members are
+// absent from the result.
+func (m MemberModel) GetByGroups(ctx context.Context, groupIds []int) (map[int][]*model.Member, error) {
+	query := `SELECT group_id, user_id, role, joined_at
+			  FROM group_members
+			  WHERE group_id = ANY($1)
+			  ORDER BY group_id, role = 'owner' DESC, joined_at, user_id;
+			`
+
+	rows, err := m.conn.QueryContext(ctx, query, pq.Array(groupIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := map[int][]*model.Member{}
+	for rows.Next() {
+		member := model.Member{}
+		if err := rows.Scan(&member.GroupId, &member.UserId, &member.Role, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		members[member.GroupId] = append(members[member.GroupId], &member)
+	}
+
+	return members, rows.Err()
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// OutboxModel provides database operations for the outbox table.
+// It holds a reference to a connection pool or transaction.
+type OutboxModel struct {
+	conn DBTX
+}
+
+// enqueueGroupEvent writes the activity to the outbox using tx, the transaction
+// of the change it describes, so the event is published if and only if the
+// change is committed.
+//
+// The members of a deleted group are deleted with it, so the webhook
+// deliveries of its deletion are queued in tx right away, while the members
+// are known, rather than when the message is published.
+func enqueueGroupEvent(ctx context.Context, tx DBTX, activity *model.Activity) error {
+	payload, err := json.Marshal(model.EventPayload{
+		Type:      activity.Type,
+		CreatedAt: activity.CreatedAt,
+		Data:      activity,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := &model.OutboxMessage{
+		EventType:     activity.Type,
+		AggregateType: model.AuditEntityGroup,
+		AggregateId:   activity.GroupId,
+		Payload:       payload,
+	}
+	if err := (OutboxModel{}).Enqueue(ctx, tx, msg); err != nil {
+		return err
+	}
+
+	if activity.Type == model.ActivityGroupDeleted {
+		return WebhookModel{conn: tx}.EnqueueDeliveries(ctx, msg)
+	}
+
+	return nil
+}
+
+// Enqueue writes a message to the outbox using tx and populates it with the
+// returned id and created_at timestamp. Pass the transaction of the change the
+// message describes.
+func (m OutboxModel) Enqueue(ctx context.Context, tx DBTX, msg *model.OutboxMessage) error {
+	query := `INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+				VALUES ($1, $2, $3, $4)
+			  RETURNING id, created_at;
+			`
+
+	return tx.QueryRowContext(
+		ctx,
+		query,
+		msg.EventType,
+		msg.AggregateType,
+		msg.AggregateId,
+		[]byte(msg.Payload),
+	).Scan(&msg.Id, &msg.CreatedAt)
+}
+
+// Process claims up to limit unpublished messages that are due, oldest first,
+// and hands each to publish. Messages that publish successfully are marked
+// published; failures are rescheduled after retryIn(attempts).
+//
+// Claimed rows stay locked until Process returns, and rows locked by another
+// dispatcher are skipped, so concurrent dispatchers never publish the same
+// message at the same time. It returns the number of messages published.
+func (m OutboxModel) Process(
+	ctx context.Context,
+	limit int,
+	publish func(ctx context.Context, msg *model.OutboxMessage) error,
+	retryIn func(attempts int) time.Duration,
+) (int, error) {
+	published := 0
+
+	err := inTx(ctx, m.conn, func(tx DBTX) error {
+		query := `
+			SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts, created_at
+			FROM outbox
+			WHERE published_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED;
+			`
+
+		rows, err := tx.QueryContext(ctx, query, limit)
+		if err != nil {
+			return err
+		}
+
+		messages := []*model.OutboxMessage{}
+		for rows.Next() {
+			msg := model.OutboxMessage{}
+			var payload []byte
+
+			err := rows.Scan(
+				&msg.Id,
+				&msg.EventType,
+				&msg.AggregateType,
+				&msg.AggregateId,
+				&payload,
+				&msg.Attempts,
+				&msg.CreatedAt,
+			)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			msg.Payload = payload
+			messages = append(messages, &msg)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if err := publish(ctx, msg); err != nil {
+				// a cancelled publish is not the message's fault; leave it untouched.
+				if ctx.Err() != nil {
+					break
+				}
+
+				_, err = tx.ExecContext(ctx,
+					`UPDATE outbox
+						SET attempts = attempts + 1,
+						last_error = $2,
+						next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+					 WHERE id = $1`,
+					msg.Id, err.Error(), retryIn(msg.Attempts+1).Seconds(),
+				)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			_, err = tx.ExecContext(ctx,
+				`UPDATE outbox SET published_at = CURRENT_TIMESTAMP, last_error = '' WHERE id = $1`,
+				msg.Id,
+			)
+			if err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitModel provides database operations for the rate_limit_buckets table,
+// which holds token buckets shared by every instance of the API.
+// It holds a reference to a connection pool or transaction.
+type RateLimitModel struct {
+	conn DBTX
+}
+
+// refilled is the number of tokens of an existing bucket after refilling it at
+// $2 tokens per second since its last update, capped at the burst size $3.
+const refilled = `LEAST($3::float8, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at))::float8 * $2::float8)`
+
+// Take refills the bucket identified by key at rate tokens per second up to
+// burst tokens and takes one token from it. A missing bucket starts full.
+// It returns the tokens left and whether a token was available. The bucket is
+// read and written in a single statement so concurrent requests never share a token.
+func (m RateLimitModel) Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		VALUES ($1, $3::float8 - 1, TRUE, now())
+		ON CONFLICT (key) DO UPDATE
+			SET allowed = ` + refilled + ` >= 1,
+			tokens = ` + refilled + ` - CASE WHEN ` + refilled + ` >= 1 THEN 1 ELSE 0 END,
+			updated_at = now()
+		RETURNING tokens, allowed;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var tokens float64
+	var allowed bool
+
+	err := m.conn.QueryRowContext(ctx, query, key, rate, burst).Scan(&tokens, &allowed)
+
+	return tokens, allowed, err
+}
+
+// DeleteIdle removes buckets not used for longer than idle. Such buckets have
+// refilled completely, so removing them does not change any limit.
+func (m RateLimitModel) DeleteIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	query := `DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => $1)`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := m.conn.ExecContext(ctx, query, idle.Seconds())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
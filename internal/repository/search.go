@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+)
+
+// Delimiters ts_headline wraps the matching words in. They are characters of the
+// private use area, which group names and descriptions have no reason to hold,
+// replaced with <mark> and </mark> by highlight once the text is escaped.
+const (
+	searchStartSel = "\uE000"
+	searchStopSel  = "\uE001"
+)
+
+// Options of ts_headline highlighting the name and description of search results.
+const (
+	searchHeadlineName        = "StartSel=" + searchStartSel + ", StopSel=" + searchStopSel + ", HighlightAll=true"
+	searchHeadlineDescription = "StartSel=" + searchStartSel + ", StopSel=" + searchStopSel + ", MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=\" … \""
+)
+
+// highlighter turns the delimiters of a ts_headline into <mark> and </mark>.
+var highlighter = strings.NewReplacer(searchStartSel, "<mark>", searchStopSel, "</mark>")
+
+// highlight returns the ts_headline of user text as HTML: the text is escaped
+// first, so that only the <mark> elements around the matching words are markup.
+func highlight(headline string) string {
+	return highlighter.Replace(html.EscapeString(headline))
+}
+
+// Search retrieves the groups matching the words of filters.Q, most relevant
+// first. Words are matched against the search_vector of groups, stemmed with
+// the text search config, e.g. english, and names are also matched by trigram
+// similarity so that a misspelled name is still found.
+//
+// The rank of a group adds the ts_rank_cd of its words, where words of the
+// name weigh more than those of the description, to the trigram similarity of
+// its name. Ties are broken by id.
+func (m GroupModel) Search(ctx context.Context, filters *model.SearchQuery, config string) ([]*model.SearchResult, model.MetaData, error) {
+	results := []*model.SearchResult{}
+	metadata := model.MetaData{}
+
+	query := `
+		SELECT count(id) OVER(), id, name, currency, description, created_by, created_at, version,
+			ts_rank_cd(search_vector, q) + similarity(name, $2) AS rank,
+			ts_headline($1::regconfig, name, q, $3),
+			ts_headline($1::regconfig, coalesce(description, ''), q, $4)
+		FROM groups, websearch_to_tsquery($1::regconfig, $2) AS q
+		WHERE
+			search_vector @@ q
+		OR
+			name % $2
+		ORDER BY rank DESC, id ASC
+		LIMIT $5 OFFSET $6;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx,
+		query,
+		config,
+		filters.Q,
+		searchHeadlineName,
+		searchHeadlineDescription,
+		filters.PageSize,
+		(filters.Page-1)*filters.PageSize,
+	)
+	if err != nil {
+		return nil, model.MetaData{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result := model.SearchResult{}
+
+		err := rows.Scan(
+			&metadata.Total,
+			&result.Group.Id,
+			&result.Group.Name,
+			&result.Group.Currency,
+			&result.Group.Description,
+			&result.Group.CreatedBy,
+			&result.Group.CreatedAt,
+			&result.Group.Version,
+			&result.Rank,
+			&result.Highlight.Name,
+			&result.Highlight.Description,
+		)
+		if err != nil {
+			return nil, model.MetaData{}, err
+		}
+
+		result.Highlight.Name = highlight(result.Highlight.Name)
+		result.Highlight.Description = highlight(result.Highlight.Description)
+
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, model.MetaData{}, err
+	}
+
+	metadata.CurrentPage = filters.Page
+	metadata.LastPage = int(math.Ceil(float64(metadata.Total) / float64(filters.PageSize)))
+	metadata.PageSize = filters.PageSize
+
+	return results, metadata, nil
+}
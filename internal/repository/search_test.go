@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// selected wraps words in the delimiters ts_headline is asked to select them with.
+func selected(words string) string {
+	return searchStartSel + words + searchStopSel
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{"Trip to " + selected("Lisbon"), "Trip to <mark>Lisbon</mark>"},
+		{"<script>alert(1)</script> " + selected("trip"), "&lt;script&gt;alert(1)&lt;/script&gt; <mark>trip</mark>"},
+		{selected("<b>") + " & \"friends\"", "<mark>&lt;b&gt;</mark> &amp; &#34;friends&#34;"},
+		{"<mark>fake</mark>", "&lt;mark&gt;fake&lt;/mark&gt;"},
+	}
+
+	for _, tt := range tests {
+		if got := highlight(tt.headline); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
+
+func TestSearchEscapesHighlights(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"count", "id", "name", "currency", "description", "created_by",
+		"created_at", "version", "rank", "name_headline", "description_headline"}).
+		AddRow(1, 3, `<img src=x onerror=alert(1)> trip`, "Euro", "", 1, "2026-01-01T00:00:00Z", 1, 0.5,
+			"<img src=x onerror=alert(1)> "+selected("trip"), "")
+
+	mock.ExpectQuery(`ts_headline`).
+		WithArgs("english", "trip", searchHeadlineName, searchHeadlineDescription, 20, 0).
+		WillReturnRows(rows)
+
+	results, _, err := NewModels(db).Groups.Search(context.Background(), &model.SearchQuery{Q: "trip", Page: 1, PageSize: 20}, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "&lt;img src=x onerror=alert(1)&gt; <mark>trip</mark>"
+	if len(results) != 1 || results[0].Highlight.Name != want {
+		t.Fatalf("Search highlights %+v, want name %q", results, want)
+	}
+
+	// the group itself is returned as stored
+	if results[0].Group.Name != `<img src=x onerror=alert(1)> trip` {
+		t.Errorf("Search name = %q, want it unescaped", results[0].Group.Name)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
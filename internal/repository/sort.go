@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal"
+)
+
+// orderBy returns the ORDER BY list of the sort keys, such as currency or
+// -created_at, naming only the columns whitelisted for their field in columns,
+// so that no text of the request reaches the SQL. Keys without a column, and
+// repeated ones, are skipped. Rows are finally sorted by id, which keeps pages
+// stable when the other columns tie.
+func orderBy(sort []string, columns map[string]string) string {
+	terms := []string{}
+	seen := map[string]bool{}
+
+	for _, key := range sort {
+		column, ok := columns[internal.GetSortValue(key)]
+		if !ok || seen[column] {
+			continue
+		}
+		seen[column] = true
+
+		terms = append(terms, column+" "+internal.GetSortDirection(key))
+	}
+
+	if !seen["id"] {
+		terms = append(terms, "id ASC")
+	}
+
+	return strings.Join(terms, ", ")
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		sort []string
+		want string
+	}{
+		{nil, "id ASC"},
+		{[]string{"name"}, "name ASC, id ASC"},
+		{[]string{"-created_at"}, "created_at DESC, id ASC"},
+		{[]string{"currency", "-created_at"}, "currency ASC, created_at DESC, id ASC"},
+		{[]string{"-id", "name"}, "id DESC, name ASC"},
+		{[]string{"name", "-name"}, "name ASC, id ASC"},
+		{[]string{"version"}, "id ASC"},
+		{[]string{"description", "-currency"}, "currency DESC, id ASC"},
+		{[]string{"name; DROP TABLE groups", "-(SELECT 1)"}, "id ASC"},
+		{[]string{"NAME"}, "id ASC"},
+	}
+
+	for _, tt := range tests {
+		if got := orderBy(tt.sort, groupSortColumns); got != tt.want {
+			t.Errorf("orderBy(%q) = %q, want %q", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestGetAllSortsByWhitelistedColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY currency ASC, created_at DESC, id ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "id", "name", "currency", "description", "created_by", "created_at", "version"}))
+
+	filters := &model.GroupQuery{Sort: []string{"currency", "-created_at", "-secret"}, Page: 1, PageSize: 20}
+	if _, _, err := NewModels(db).Groups.GetAll(t.Context(), filters); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
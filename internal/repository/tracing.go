@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+
+	"github.com/Abdul4code/FairShare/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedConn wraps a DBTX and records a span for every statement it runs. The
+// span is named after the repository function issuing the statement, e.g.
+// "GroupModel.GetAll", and carries the SQL text as db.statement.
+type tracedConn struct {
+	conn DBTX
+}
+
+// traced wraps conn so its statements are traced.
+func traced(conn DBTX) DBTX {
+	if _, ok := conn.(tracedConn); ok {
+		return conn
+	}
+	return tracedConn{conn}
+}
+
+// untraced returns the connection wrapped by traced.
+func untraced(conn DBTX) DBTX {
+	if t, ok := conn.(tracedConn); ok {
+		return t.conn
+	}
+	return conn
+}
+
+// ExecContext implements DBTX.
+func (c tracedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startStatementSpan(ctx, query)
+	defer span.End()
+
+	res, err := c.conn.ExecContext(ctx, query, args...)
+	recordError(span, err)
+
+	return res, err
+}
+
+// QueryContext implements DBTX. The span covers running the query, not
+// iterating over its rows.
+func (c tracedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startStatementSpan(ctx, query)
+	defer span.End()
+
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	recordError(span, err)
+
+	return rows, err
+}
+
+// QueryRowContext implements DBTX.
+func (c tracedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startStatementSpan(ctx, query)
+	defer span.End()
+
+	row := c.conn.QueryRowContext(ctx, query, args...)
+	recordError(span, row.Err())
+
+	return row
+}
+
+// startStatementSpan starts a client span for query named after the repository
+// function two frames up the stack, the one that called the tracedConn method.
+func startStatementSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := "sql"
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		name = statementName(runtime.FuncForPC(pc).Name())
+	}
+
+	statement := strings.Join(strings.Fields(query), " ")
+	operation, _, _ := strings.Cut(statement, " ")
+
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", strings.ToUpper(operation)),
+			attribute.String("db.statement", statement),
+		),
+	)
+}
+
+// statementName turns a fully qualified function name such as
+// ".../repository.GroupModel.Update.func1" into "GroupModel.Update".
+func statementName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	name = strings.TrimPrefix(name, "repository.")
+	name = strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)
+
+	parts := strings.Split(name, ".")
+	for len(parts) > 1 && strings.HasPrefix(parts[len(parts)-1], "func") {
+		parts = parts[:len(parts)-1]
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// recordError marks the span as failed when err is not nil.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
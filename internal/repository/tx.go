@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// maxTxAttempts is the number of times WithTx runs a transaction that keeps
+// failing with a serialization failure or deadlock.
+const maxTxAttempts = 3
+
+// WithTx runs fn in a single database transaction. The Models passed to fn are
+// bound to the transaction, so every write fn makes through them is committed
+// when fn returns nil and rolled back when it returns an error or panics.
+//
+// Transactions failing with a serialization failure (SQLSTATE 40001) or a
+// deadlock (40P01) are retried from the start, so fn must not have side
+// effects outside the database. WithTx called on Models already bound to a
+// transaction runs fn in that transaction.
+func (m *Models) WithTx(ctx context.Context, fn func(tx *Models) error) error {
+	return m.WithTxOptions(ctx, nil, fn)
+}
+
+// WithTxOptions is like WithTx but starts the transaction with opts, for
+// example to request sql.LevelSerializable isolation.
+func (m *Models) WithTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(tx *Models) error) error {
+	if m.db == nil {
+		return fn(m)
+	}
+
+	var err error
+
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = runTx(ctx, m.db, opts, func(tx *sql.Tx) error {
+			return fn(newModels(tx))
+		})
+
+		if !isRetryable(err) || attempt == maxTxAttempts {
+			break
+		}
+
+		// back off with jitter so conflicting transactions do not collide again.
+		delay := time.Duration(attempt)*10*time.Millisecond + rand.N(10*time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return err
+}
+
+// inTx runs fn in a transaction on conn. When conn already is a transaction,
+// fn joins it and the owner of that transaction decides commit or rollback.
+func inTx(ctx context.Context, conn DBTX, fn func(tx DBTX) error) error {
+	db, ok := untraced(conn).(*sql.DB)
+	if !ok {
+		return fn(conn)
+	}
+
+	return runTx(ctx, db, nil, func(tx *sql.Tx) error {
+		return fn(traced(tx))
+	})
+}
+
+// runTx begins a transaction on db, runs fn and commits when fn returns nil.
+// The transaction is rolled back on error or panic.
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isRetryable reports whether err is a serialization failure or deadlock, after
+// which the whole transaction can safely be run again.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
@@ -0,0 +1,432 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/lib/pq"
+)
+
+// WebhookModel provides database operations for the webhooks and
+// webhook_deliveries tables. It holds a reference to a connection pool or transaction.
+type WebhookModel struct {
+	conn DBTX
+}
+
+// DueDelivery is a delivery claimed for sending, joined with the receiver
+// details of its webhook.
+type DueDelivery struct {
+	Id        int64
+	WebhookId int
+	EventType string
+	Payload   []byte
+	Attempts  int
+	URL       string
+	Secret    string
+}
+
+// EnqueueDeliveries queues a delivery of the outbox message to every active
+// webhook subscribed to its event type whose owner is a member of the group the
+// message is about, so that users only receive the events of their groups.
+// Enqueueing the same message again does not create duplicate deliveries.
+func (m WebhookModel) EnqueueDeliveries(ctx context.Context, msg *model.OutboxMessage) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_type, payload, outbox_id)
+				SELECT w.id, $1, $2, $3 FROM webhooks w
+				JOIN group_members gm ON gm.user_id = w.created_by AND gm.group_id = $4
+				WHERE w.active AND (cardinality(w.event_types) = 0 OR $1 = ANY(w.event_types))
+			  ON CONFLICT (webhook_id, outbox_id) DO NOTHING
+			`
+
+	_, err := m.conn.ExecContext(ctx, query, msg.EventType, []byte(msg.Payload), msg.Id, msg.AggregateId)
+	return err
+}
+
+// Insert inserts a new webhook row into the database and populates the given
+// model.Webhook with the returned id and created_at timestamp.
+func (m WebhookModel) Insert(ctx context.Context, data *model.Webhook) error {
+	query := `INSERT INTO webhooks (url, secret, event_types, active, created_by)
+				VALUES ($1, $2, $3, $4, $5)
+			  RETURNING id, created_at;
+			`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return m.conn.QueryRowContext(
+		ctx,
+		query,
+		data.URL,
+		data.Secret,
+		pq.Array(data.EventTypes),
+		data.Active,
+		data.CreatedBy,
+	).Scan(&data.Id, &data.CreatedAt)
+}
+
+// Get retrieves a webhook, including its secret, by its integer id. It returns
+// internal.ErrNotFound when no row is found.
+func (m WebhookModel) Get(ctx context.Context, id int) (*model.Webhook, error) {
+	if id < 1 {
+		return nil, internal.ErrNotFound
+	}
+
+	query := `SELECT id, url, secret, event_types, active, created_by, created_at
+			  FROM webhooks WHERE id = $1;
+			`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhook, err := scanWebhook(m.conn.QueryRowContext(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, internal.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return webhook, nil
+}
+
+// GetAll retrieves a page of webhooks ordered by id, restricted to those
+// created by filters.CreatedBy unless it is zero. Secrets are not returned.
+func (m WebhookModel) GetAll(ctx context.Context, filters *model.WebhookQuery) ([]*model.Webhook, model.MetaData, error) {
+	webhooks := []*model.Webhook{}
+	metadata := model.MetaData{}
+
+	query := `
+		SELECT count(id) OVER(), id, url, '', event_types, active, created_by, created_at
+		FROM webhooks
+		WHERE created_by = $3 OR $3 = 0
+		ORDER BY id ASC
+		LIMIT $1 OFFSET $2;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx, query, filters.PageSize, (filters.Page-1)*filters.PageSize, filters.CreatedBy)
+	if err != nil {
+		return nil, model.MetaData{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		webhook, err := scanWebhook(rows, &metadata.Total)
+		if err != nil {
+			return nil, model.MetaData{}, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, model.MetaData{}, err
+	}
+
+	metadata.CurrentPage = filters.Page
+	metadata.LastPage = int(math.Ceil(float64(metadata.Total) / float64(filters.PageSize)))
+	metadata.PageSize = filters.PageSize
+
+	return webhooks, metadata, nil
+}
+
+// Update writes the url, secret, event types and active flag of an existing
+// webhook. It returns internal.ErrNotFound when no row matches data.Id.
+func (m WebhookModel) Update(ctx context.Context, data *model.Webhook) error {
+	if data.Id < 1 {
+		return internal.ErrNotFound
+	}
+
+	query := `UPDATE webhooks
+				SET url = $1,
+				secret = $2,
+				event_types = $3,
+				active = $4
+			  WHERE id = $5
+			`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, err := m.conn.ExecContext(
+		ctx,
+		query,
+		data.URL,
+		data.Secret,
+		pq.Array(data.EventTypes),
+		data.Active,
+		data.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	affectedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return internal.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a webhook and its deliveries by id. It returns
+// internal.ErrNotFound when the id is invalid or no row was deleted.
+func (m WebhookModel) Delete(ctx context.Context, id int) error {
+	if id < 1 {
+		return internal.ErrNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, err := m.conn.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	affectedRows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return internal.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetDeliveries retrieves a page of a webhook's deliveries, newest first,
+// optionally filtered by status.
+func (m WebhookModel) GetDeliveries(ctx context.Context, filters *model.WebhookQuery) ([]*model.WebhookDelivery, model.MetaData, error) {
+	deliveries := []*model.WebhookDelivery{}
+	metadata := model.MetaData{}
+
+	query := `
+		SELECT count(id) OVER(), id, webhook_id, event_type, payload, status, attempts,
+			next_attempt_at, last_status_code, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND (status = $2 OR $2 = '')
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := m.conn.QueryContext(ctx,
+		query,
+		filters.WebhookId,
+		filters.Status,
+		filters.PageSize,
+		(filters.Page-1)*filters.PageSize,
+	)
+	if err != nil {
+		return nil, model.MetaData{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows, &metadata.Total)
+		if err != nil {
+			return nil, model.MetaData{}, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, model.MetaData{}, err
+	}
+
+	metadata.CurrentPage = filters.Page
+	metadata.LastPage = int(math.Ceil(float64(metadata.Total) / float64(filters.PageSize)))
+	metadata.PageSize = filters.PageSize
+
+	return deliveries, metadata, nil
+}
+
+// Redeliver queues a fresh copy of an existing delivery of the webhook and
+// returns it. It returns internal.ErrNotFound when the delivery does not belong
+// to the webhook.
+func (m WebhookModel) Redeliver(ctx context.Context, webhookId int, deliveryId int64) (*model.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+		SELECT webhook_id, event_type, payload FROM webhook_deliveries
+		WHERE id = $1 AND webhook_id = $2
+		RETURNING id, webhook_id, event_type, payload, status, attempts,
+			next_attempt_at, last_status_code, last_error, created_at, delivered_at;
+		`
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	delivery, err := scanDelivery(m.conn.QueryRowContext(ctx, query, deliveryId, webhookId))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, internal.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return delivery, nil
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due and
+// counts the attempt. Claimed rows are leased for lease, during which no other
+// dispatcher claims them; rows locked by another dispatcher are skipped.
+func (m WebhookModel) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*DueDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND w.active
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+			SET attempts = d.attempts + 1,
+			next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_type, d.payload, d.attempts, w.url, w.secret;
+		`
+
+	rows, err := m.conn.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*DueDelivery{}
+
+	for rows.Next() {
+		d := DueDelivery{}
+		err := rows.Scan(&d.Id, &d.WebhookId, &d.EventType, &d.Payload, &d.Attempts, &d.URL, &d.Secret)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkDelivered records a successful delivery attempt.
+func (m WebhookModel) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	query := `UPDATE webhook_deliveries
+				SET status = 'delivered',
+				last_status_code = $2,
+				last_error = '',
+				delivered_at = CURRENT_TIMESTAMP
+			  WHERE id = $1
+			`
+
+	_, err := m.conn.ExecContext(ctx, query, id, statusCode)
+	return err
+}
+
+// MarkFailed records a failed delivery attempt. A nil retryAt gives up on the
+// delivery; otherwise it is attempted again at retryAt. A zero statusCode
+// means no response was received.
+func (m WebhookModel) MarkFailed(ctx context.Context, id int64, statusCode int, cause string, retryAt *time.Time) error {
+	query := `UPDATE webhook_deliveries
+				SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+				last_status_code = NULLIF($2, 0),
+				last_error = $3,
+				next_attempt_at = COALESCE($4::timestamptz, next_attempt_at)
+			  WHERE id = $1
+			`
+
+	_, err := m.conn.ExecContext(ctx, query, id, statusCode, cause, retryAt)
+	return err
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanWebhook reads a row selected as
+// id, url, secret, event_types, active, created_by, created_at, optionally
+// preceded by the columns in prefix.
+func scanWebhook(row scanner, prefix ...any) (*model.Webhook, error) {
+	webhook := &model.Webhook{}
+	createdBy := sql.NullInt64{}
+
+	dest := append(prefix,
+		&webhook.Id,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.EventTypes),
+		&webhook.Active,
+		&createdBy,
+		&webhook.CreatedAt,
+	)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		webhook.CreatedBy = &id
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	return webhook, nil
+}
+
+// scanDelivery reads a row selected as id, webhook_id, event_type, payload,
+// status, attempts, next_attempt_at, last_status_code, last_error, created_at,
+// delivered_at, optionally preceded by the columns in prefix.
+func scanDelivery(row scanner, prefix ...any) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{}
+	statusCode := sql.NullInt64{}
+	deliveredAt := sql.NullString{}
+	var payload []byte
+
+	dest := append(prefix,
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&statusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		delivery.LastStatusCode = &code
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.String
+	}
+
+	return delivery, nil
+}
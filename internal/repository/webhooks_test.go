@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/Abdul4code/FairShare/internal/model"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestEnqueueDeliveriesOnlyToMembers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	msg := &model.OutboxMessage{Id: 5, EventType: model.ActivityGroupRenamed, AggregateType: model.AuditEntityGroup, AggregateId: 3, Payload: []byte(`{}`)}
+
+	// the webhooks of users outside group 3 are joined out of the deliveries
+	mock.ExpectExec(regexp.QuoteMeta(`JOIN group_members gm ON gm.user_id = w.created_by AND gm.group_id = $4`)).
+		WithArgs(msg.EventType, []byte(msg.Payload), msg.Id, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := NewModels(db).Webhooks.EnqueueDeliveries(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeleteGroupEnqueuesDeliveriesBeforeDeleting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "currency", "description", "created_by", "created_at", "version"}).
+			AddRow(3, "Trip", "Euro", "", 1, "2026-01-01T00:00:00Z", 1))
+	mock.ExpectExec(`INSERT INTO audit_events`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO activities`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, "2026-01-01T00:00:00Z"))
+	mock.ExpectQuery(`INSERT INTO outbox`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, "2026-01-01T00:00:00Z"))
+
+	// the members are still there to be matched with webhooks
+	mock.ExpectExec(`INSERT INTO webhook_deliveries .+ JOIN group_members`).
+		WithArgs(model.ActivityGroupDeleted, sqlmock.AnyArg(), int64(5), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_notify`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM groups`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewModels(db).Groups.DeleteGroup(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpFileClient is the otlptrace client of the file exporter. Each batch of
+// spans is written as an OTLP ExportTraceServiceRequest in the OTLP/JSON
+// encoding, on a line of its own, the format of the file exporter of the
+// OpenTelemetry Collector, which its otlpjsonfile receiver reads back.
+type otlpFileClient struct {
+	mu  sync.Mutex
+	out io.WriteCloser
+}
+
+// Start does nothing, the file is opened by Setup.
+func (c *otlpFileClient) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop closes the file.
+func (c *otlpFileClient) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.out.Close()
+}
+
+// UploadTraces appends spans to the file as a line of OTLP/JSON.
+func (c *otlpFileClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	// the protobuf JSON mapping encodes trace and span ids in base64 where
+	// OTLP/JSON wants hex, so the request goes through the Collector's encoder
+	data, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		return err
+	}
+
+	traces, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(data)
+	if err != nil {
+		return err
+	}
+
+	line, err := (&ptrace.JSONMarshaler{}).MarshalTraces(traces)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err = c.out.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported span exporters.
+const (
+	ExporterNone   = "none"   // spans are created for propagation but not exported
+	ExporterStdout = "stdout" // spans are written to stdout in the readable JSON of stdouttrace, which is not OTLP
+	ExporterFile   = "file"   // spans are written to Config.File as OTLP/JSON lines
+)
+
+// instrumentationName identifies the tracer used throughout the API.
+const instrumentationName = "github.com/Abdul4code/FairShare"
+
+// Config holds the tracing settings.
+type Config struct {
+	Exporter    string // none | stdout | file
+	File        string // output file of the file exporter
+	ServiceName string // service.name resource attribute
+	Version     string // service.version resource attribute
+}
+
+// Setup installs the global tracer provider described by cfg and the W3C trace
+// context propagator, so incoming traceparent headers continue the caller's
+// trace. The returned function flushes pending spans and releases the exporter.
+func Setup(cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+	case ExporterStdout:
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout)); err != nil {
+			return nil, err
+		}
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		// the file is closed by the exporter when the provider shuts down
+		if exporter, err = otlptrace.New(context.Background(), &otlpFileClient{out: f}); err != nil {
+			f.Close()
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", cfg.Exporter)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.Version),
+	)
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the API from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileExporterWritesOTLPJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+
+	shutdown, err := Setup(Config{Exporter: ExporterFile, File: path, ServiceName: "fairshare-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "GET /v1/groups/:id")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value struct {
+						StringValue string `json:"stringValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceId string `json:"traceId"`
+					SpanId  string `json:"spanId"`
+					Name    string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}{}
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatalf("the file is not a line of JSON: %v\n%s", err, data)
+	}
+
+	if len(request.ResourceSpans) != 1 || len(request.ResourceSpans[0].ScopeSpans) != 1 || len(request.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("file holds %s, want a single span", data)
+	}
+
+	span0 := request.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	if span0.Name != "GET /v1/groups/:id" {
+		t.Errorf("span name %q, want GET /v1/groups/:id", span0.Name)
+	}
+
+	// OTLP/JSON encodes ids in hex, not in base64 like the protobuf JSON mapping
+	if id, err := hex.DecodeString(span0.TraceId); err != nil || len(id) != 16 {
+		t.Errorf("traceId %q, want 32 hex digits", span0.TraceId)
+	}
+	if id, err := hex.DecodeString(span0.SpanId); err != nil || len(id) != 8 {
+		t.Errorf("spanId %q, want 16 hex digits", span0.SpanId)
+	}
+
+	found := false
+	for _, attribute := range request.ResourceSpans[0].Resource.Attributes {
+		found = found || (attribute.Key == "service.name" && attribute.Value.StringValue == "fairshare-test")
+	}
+	if !found {
+		t.Error("the resource has no service.name")
+	}
+}
@@ -0,0 +1,204 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
+)
+
+// RuleFunc reports whether value satisfies a rule. param is the text after
+// "=" in the tag (e.g. "2" for min=2), empty for rules without a parameter.
+// Pointers are dereferenced before a rule is called.
+type RuleFunc func(value reflect.Value, param string) bool
+
+// rule is a registered validation rule
+type rule struct {
+	fn      RuleFunc
+	message string
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]rule{}
+)
+
+// Register adds a rule that can be used in validate tags under name, replacing
+// any rule of the same name. message is reported for fields failing the rule;
+// {field} in it is replaced by the field name and {param} by the rule parameter.
+//
+// Messages are translated through the i18n catalogs under the code
+// validation.<name>, or validation.<name>.<kind> when the wording depends on
+// the kind of value (string, slice or number); message is used when the
+// catalogs have no translation.
+func Register(name string, fn RuleFunc, message string) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	rules[name] = rule{fn: fn, message: message}
+}
+
+// lookupRule returns the registered rule of the given name.
+func lookupRule(name string) (rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	r, ok := rules[name]
+	return r, ok
+}
+
+// ruleMessage returns the message of rule name in lang for a value of the
+// given kind, with {field} and {param} filled in.
+func ruleMessage(lang string, name string, kind string, field string, param string) string {
+	args := i18n.Args{"field": field, "param": strings.Join(strings.Fields(param), ", ")}
+
+	if message, ok := i18n.Lookup(lang, "validation."+name+"."+kind); ok {
+		return i18n.Format(message, args)
+	}
+
+	if message, ok := i18n.Lookup(lang, "validation."+name); ok {
+		return i18n.Format(message, args)
+	}
+
+	rulesMu.RLock()
+	message := rules[name].message
+	rulesMu.RUnlock()
+
+	return i18n.Format(message, args)
+}
+
+// kindName groups reflect kinds the way rule messages are worded.
+func kindName(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "slice"
+	default:
+		return "number"
+	}
+}
+
+func init() {
+	Register("required", required, "{field} must be provided")
+	Register("min", minimum, "{field} must be at least {param}")
+	Register("max", maximum, "{field} must be at most {param}")
+	Register("len", length, "{field} must have a length of {param}")
+	Register("oneof", oneOf, "{field} must be one of [{param}]")
+	Register("email", email, "{field} must be a valid email address")
+	Register("url", absoluteURL, "{field} must be an absolute http or https URL")
+	Register("unique", unique, "{field} must not contain duplicate values")
+}
+
+// required checks the value is not the zero value of its type.
+func required(value reflect.Value, param string) bool {
+	return value.IsValid() && !value.IsZero()
+}
+
+// size returns the length of strings (in characters), slices and maps, and the
+// value of numbers, as compared by min, max and len.
+func size(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+
+	return 0, false
+}
+
+// compare parses param as a number and compares it with the size of value.
+func compare(value reflect.Value, param string, ok func(size float64, limit float64) bool) bool {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid rule parameter %q", param))
+	}
+
+	s, valid := size(value)
+	return valid && ok(s, limit)
+}
+
+// minimum checks the size of the value is at least param.
+func minimum(value reflect.Value, param string) bool {
+	return compare(value, param, func(s, limit float64) bool { return s >= limit })
+}
+
+// maximum checks the size of the value is at most param.
+func maximum(value reflect.Value, param string) bool {
+	return compare(value, param, func(s, limit float64) bool { return s <= limit })
+}
+
+// length checks the size of the value is exactly param.
+func length(value reflect.Value, param string) bool {
+	return compare(value, param, func(s, limit float64) bool { return s == limit })
+}
+
+// oneOf checks the value is one of the space separated values of param.
+func oneOf(value reflect.Value, param string) bool {
+	return slices.Contains(strings.Fields(param), fmt.Sprint(value.Interface()))
+}
+
+// email checks the value is a string shaped like an email address.
+func email(value reflect.Value, param string) bool {
+	return value.Kind() == reflect.String && EmailRegex.MatchString(value.String())
+}
+
+// absoluteURL checks the value is an absolute http or https URL.
+func absoluteURL(value reflect.Value, param string) bool {
+	if value.Kind() != reflect.String {
+		return false
+	}
+
+	u, err := url.Parse(value.String())
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// unique checks the elements of a slice or array are distinct. Elements that
+// cannot be map keys, such as slices, are compared with reflect.DeepEqual.
+func unique(value reflect.Value, param string) bool {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return false
+	}
+
+	seen := map[any]bool{}
+	for i := range value.Len() {
+		if !value.Index(i).Comparable() {
+			return uniqueDeep(value)
+		}
+
+		element := value.Index(i).Interface()
+		if seen[element] {
+			return false
+		}
+		seen[element] = true
+	}
+
+	return true
+}
+
+// uniqueDeep checks the elements of a slice or array are distinct by comparing
+// every pair with reflect.DeepEqual.
+func uniqueDeep(value reflect.Value) bool {
+	for i := range value.Len() {
+		for j := range i {
+			if reflect.DeepEqual(value.Index(i).Interface(), value.Index(j).Interface()) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldRules holds the parsed validate tag of a struct field.
+type fieldRules struct {
+	index int
+	name  string    // JSON name of the field, used as error key
+	rules []tagRule // rules applied to the field
+	dive  []tagRule // rules applied to each element of a slice field
+}
+
+// tagRule is a single rule of a validate tag, e.g. min=2.
+type tagRule struct {
+	name  string
+	param string
+}
+
+// structCache holds the parsed fields of each validated struct type.
+var structCache sync.Map
+
+// Struct validates the exported fields of the struct s, or pointer to one,
+// according to their validate tags and adds an error per failing field. It
+// returns whether s is valid.
+//
+// Tags list rules separated by commas, e.g. `validate:"required,min=2,max=255"`.
+// Fields holding a zero value skip their remaining rules after omitempty, and
+// nil pointers are only checked by required. Rules after dive are applied to
+// every element of a slice. Nested structs, and structs within slices, are
+// validated recursively. Errors are keyed by the JSON names of the fields,
+// joined with dots for nested structs and indexed for slices
+// (e.g. members[0].name). Only the first failing rule of a field is reported,
+// and fields that already have an error are not checked again.
+func (val *Validator) Struct(s any) bool {
+	value := reflect.ValueOf(s)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return val.Valid()
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: Struct called with %s, expected a struct", value.Type()))
+	}
+
+	val.validateStruct(value, "")
+	return val.Valid()
+}
+
+// validateStruct validates the fields of the struct value, prefixing error keys with prefix.
+func (val *Validator) validateStruct(value reflect.Value, prefix string) {
+	for _, field := range parseStruct(value.Type()) {
+		val.validateField(value.Field(field.index), prefix+field.name, field.rules, field.dive)
+	}
+}
+
+// validateField applies rules to value, then dive rules to each of its elements,
+// and recurses into nested structs.
+func (val *Validator) validateField(value reflect.Value, key string, rules []tagRule, dive []tagRule) {
+	// keep errors added before, such as a query parameter that failed to parse
+	if _, exists := val.Errors[key]; exists {
+		return
+	}
+
+	for _, r := range rules {
+		if r.name == "omitempty" {
+			if !value.IsValid() || value.IsZero() {
+				return
+			}
+			continue
+		}
+
+		if value.Kind() == reflect.Pointer && r.name != "required" {
+			if value.IsNil() {
+				return
+			}
+			value = value.Elem()
+		}
+
+		registered, ok := lookupRule(r.name)
+		if !ok {
+			panic(fmt.Sprintf("validation: unknown rule %q", r.name))
+		}
+
+		if !registered.fn(value, r.param) {
+			val.Add(key, ruleMessage(val.Lang, r.name, kindName(value), key, r.param))
+			return
+		}
+	}
+
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		val.validateStruct(value, key+".")
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			val.validateField(value.Index(i), fmt.Sprintf("%s[%d]", key, i), dive, nil)
+		}
+	}
+}
+
+// parseStruct returns the validated fields of the struct type t.
+func parseStruct(t reflect.Type) []fieldRules {
+	if cached, ok := structCache.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+
+	fields := []fieldRules{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		tag := field.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		f := fieldRules{index: i, name: name}
+
+		target := &f.rules
+		for part := range strings.SplitSeq(tag, ",") {
+			part = strings.TrimSpace(part)
+			switch part {
+			case "":
+				continue
+			case "dive":
+				target = &f.dive
+				continue
+			}
+
+			name, param, _ := strings.Cut(part, "=")
+			*target = append(*target, tagRule{name: name, param: param})
+		}
+
+		fields = append(fields, f)
+	}
+
+	structCache.Store(t, fields)
+	return fields
+}
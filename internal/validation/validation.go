@@ -2,11 +2,14 @@ package validation
 
 import (
 	"regexp"
+
+	"github.com/Abdul4code/FairShare/internal/i18n"
 )
 
 // Validator struct holds validation errors
 type Validator struct {
 	Errors map[string]string
+	Lang   string // language of the error messages
 }
 
 // Define regex rule for email
@@ -14,8 +17,14 @@ var EmailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-
 
 // New creates and returns a new Validator instance
 func New() *Validator {
+	return NewLocalized(i18n.DefaultLanguage)
+}
+
+// NewLocalized creates and returns a new Validator reporting messages in lang
+func NewLocalized(lang string) *Validator {
 	return &Validator{
 		Errors: map[string]string{},
+		Lang:   lang,
 	}
 }
 
@@ -29,6 +38,18 @@ func (val *Validator) Add(key string, message string) {
 	val.Errors[key] = message
 }
 
+// AddCode adds the message of code, translated to the validator language, for a given key
+func (val *Validator) AddCode(key string, code string, args i18n.Args) {
+	val.Add(key, i18n.T(val.Lang, code, args))
+}
+
+// CheckCode adds the message of code for the given key if the condition is false
+func (val *Validator) CheckCode(ok bool, key string, code string, args i18n.Args) {
+	if !ok {
+		val.AddCode(key, code, args)
+	}
+}
+
 // Check adds an error message for the given key if the condition is false
 func (val *Validator) Check(ok bool, key string, message string) {
 	if !ok {
@@ -62,5 +83,5 @@ func (val *Validator) Unique(collections []string) bool {
 		uniqueMap[val] = 1
 	}
 
-	return len(uniqueMap) <= len(collections)
+	return len(uniqueMap) == len(collections)
 }
@@ -0,0 +1,209 @@
+package validation
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+)
+
+type testMember struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+type testGroup struct {
+	Name     string       `json:"name" validate:"required,min=2,max=10"`
+	Currency string       `json:"currency" validate:"oneof=Euro Pound"`
+	Code     string       `json:"code" validate:"omitempty,len=3"`
+	Website  string       `json:"website" validate:"omitempty,url"`
+	Limit    *int         `json:"limit" validate:"min=1"`
+	Owner    *int         `json:"owner" validate:"required"`
+	Tags     []string     `json:"tags" validate:"max=2,unique,dive,min=2"`
+	Members  []testMember `json:"members" validate:"min=1"`
+	Ignored  string       `json:"-" validate:"required"`
+	Skipped  string       `json:"skipped" validate:"-"`
+	internal string       `validate:"required"`
+}
+
+// validGroup returns a group passing every rule of testGroup.
+func validGroup() testGroup {
+	owner := 1
+	return testGroup{
+		Name:     "Trip",
+		Currency: "Euro",
+		Owner:    &owner,
+		Members:  []testMember{{Name: "Ada"}},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	zero, two, limit := 0, 2, 3
+
+	tests := []struct {
+		name   string
+		modify func(g *testGroup)
+		errors map[string]string
+	}{
+		{"valid", func(g *testGroup) {}, map[string]string{}},
+		{"valid optional fields", func(g *testGroup) {
+			g.Code = "EUR"
+			g.Website = "https://example.com"
+			g.Limit = &limit
+			g.Tags = []string{"ab", "cd"}
+			g.Members[0].Email = "ada@example.com"
+		}, map[string]string{}},
+		{"required", func(g *testGroup) { g.Name = "" }, map[string]string{
+			"name": "name must be provided",
+		}},
+		{"first failing rule only", func(g *testGroup) { g.Name = "x" }, map[string]string{
+			"name": "name must be at least 2 characters long",
+		}},
+		{"characters not bytes", func(g *testGroup) { g.Name = "éééééééééé" }, map[string]string{}},
+		{"max string", func(g *testGroup) { g.Name = "a long group name" }, map[string]string{
+			"name": "name must be at most 10 characters long",
+		}},
+		{"oneof", func(g *testGroup) { g.Currency = "Yen" }, map[string]string{
+			"currency": "currency must be one of: Euro, Pound",
+		}},
+		{"len", func(g *testGroup) { g.Code = "EURO" }, map[string]string{
+			"code": "code must be 3 characters long",
+		}},
+		{"url", func(g *testGroup) { g.Website = "ftp://example.com" }, map[string]string{
+			"website": "website must be an absolute http or https URL",
+		}},
+		{"pointer", func(g *testGroup) { g.Limit = &zero }, map[string]string{
+			"limit": "limit must be at least 1",
+		}},
+		{"nil pointer only checked by required", func(g *testGroup) { g.Owner = nil }, map[string]string{
+			"owner": "owner must be provided",
+		}},
+		{"required pointer to zero", func(g *testGroup) { g.Owner = &zero; g.Limit = &two }, map[string]string{}},
+		{"max slice", func(g *testGroup) { g.Tags = []string{"ab", "cd", "ef"} }, map[string]string{
+			"tags": "tags must contain at most 2 items",
+		}},
+		{"unique", func(g *testGroup) { g.Tags = []string{"ab", "ab"} }, map[string]string{
+			"tags": "tags must not contain duplicate values",
+		}},
+		{"dive", func(g *testGroup) { g.Tags = []string{"ab", "c"} }, map[string]string{
+			"tags[1]": "tags[1] must be at least 2 characters long",
+		}},
+		{"min slice", func(g *testGroup) { g.Members = nil }, map[string]string{
+			"members": "members must contain at least 1 items",
+		}},
+		{"nested", func(g *testGroup) {
+			g.Members = append(g.Members, testMember{Name: "Grace Hopper", Email: "grace"})
+		}, map[string]string{
+			"members[1].name":  "members[1].name must be at most 5 characters long",
+			"members[1].email": "members[1].email must be a valid email address",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := validGroup()
+			tt.modify(&group)
+
+			v := New()
+			if valid := v.Struct(&group); valid != (len(tt.errors) == 0) {
+				t.Errorf("Struct() = %v with errors %v", valid, v.Errors)
+			}
+			if !maps.Equal(v.Errors, tt.errors) {
+				t.Errorf("Errors = %v, want %v", v.Errors, tt.errors)
+			}
+		})
+	}
+}
+
+func TestStructKeepsExistingErrors(t *testing.T) {
+	group := validGroup()
+	group.Name = ""
+
+	v := New()
+	v.Add("name", "name could not be read")
+
+	if v.Struct(group) {
+		t.Fatal("Struct() = true, want false")
+	}
+	if got := v.Errors["name"]; got != "name could not be read" {
+		t.Errorf("Errors[name] = %q, want the error added first", got)
+	}
+}
+
+func TestStructLocalized(t *testing.T) {
+	group := validGroup()
+	group.Name = "x"
+
+	v := NewLocalized("fr")
+	v.Struct(group)
+
+	if got, want := v.Errors["name"], "name doit contenir au moins 2 caractères"; got != want {
+		t.Errorf("Errors[name] = %q, want %q", got, want)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("even", func(value reflect.Value, param string) bool {
+		return value.Int()%2 == 0
+	}, "{field} must be even")
+
+	s := struct {
+		Count int `json:"count" validate:"even"`
+	}{Count: 3}
+
+	v := New()
+	if v.Struct(s) {
+		t.Fatal("Struct() = true, want false")
+	}
+	if got := v.Errors["count"]; got != "count must be even" {
+		t.Errorf("Errors[count] = %q, want the registered message", got)
+	}
+}
+
+func TestUniqueUncomparable(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{"distinct slices", [][]string{{"a"}, {"b"}}, true},
+		{"duplicate slices", [][]string{{"a"}, {"b"}, {"a"}}, false},
+		{"distinct maps", []map[string]int{{"a": 1}, {"a": 2}}, true},
+		{"duplicate interfaces", []any{1, []int{2}, []int{2}}, false},
+		{"distinct interfaces", []any{1, []int{2}, "2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unique(reflect.ValueOf(tt.value), ""); got != tt.want {
+				t.Errorf("unique(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructPanics(t *testing.T) {
+	tests := map[string]any{
+		"not a struct": 3,
+		"unknown rule": struct {
+			Name string `validate:"shiny"`
+		}{Name: "x"},
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Struct did not panic")
+				}
+			}()
+			New().Struct(value)
+		})
+	}
+}
+
+func TestStructNilPointer(t *testing.T) {
+	var group *testGroup
+	if !New().Struct(group) {
+		t.Error("Struct(nil) = false, want true")
+	}
+}
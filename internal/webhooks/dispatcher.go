@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal"
+	"github.com/Abdul4code/FairShare/internal/netguard"
+	"github.com/Abdul4code/FairShare/internal/repository"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderEvent     = "X-FairShare-Event"
+	HeaderDelivery  = "X-FairShare-Delivery"
+	HeaderTimestamp = "X-FairShare-Timestamp"
+	HeaderSignature = "X-FairShare-Signature"
+)
+
+// Dispatcher periodically claims due webhook deliveries, sends them and records
+// the outcome, retrying failed deliveries with exponential backoff.
+type Dispatcher struct {
+	Webhooks     repository.WebhookModel
+	Client       *http.Client
+	Interval     time.Duration // time between polls for due deliveries
+	BatchSize    int           // maximum deliveries claimed per poll
+	MaxAttempts  int           // attempts before a delivery is marked failed
+	BaseBackoff  time.Duration // delay before the first retry, doubled per attempt
+	MaxBackoff   time.Duration // upper bound of the delay between attempts
+	LeaseTimeout time.Duration // time a claimed delivery is hidden from other dispatchers
+}
+
+// NewDispatcher returns a Dispatcher for the given webhook model with the
+// default polling and retry settings. Its client refuses to connect to
+// addresses that are not public, see NewClient.
+func NewDispatcher(webhooks repository.WebhookModel) *Dispatcher {
+	return &Dispatcher{
+		Webhooks:     webhooks,
+		Client:       NewClient(10 * time.Second),
+		Interval:     5 * time.Second,
+		BatchSize:    20,
+		MaxAttempts:  10,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+		LeaseTimeout: time.Minute,
+	}
+}
+
+// NewClient returns the client deliveries are sent with. Receiver URLs are
+// chosen by users, so the addresses their host names resolve to are checked
+// when each connection is dialed, including connections of redirects, and
+// loopback, private, link-local and other non-public addresses are refused.
+// Proxies are not used, as they would dial the receiver on the client's behalf.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: netguard.Control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// Run dispatches due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			internal.NewLogger().Log.Error().Err(err).Msg("failed to dispatch webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims one batch of due deliveries and sends them concurrently.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	deliveries, err := d.Webhooks.ClaimDue(ctx, d.BatchSize, d.LeaseTimeout)
+	if err != nil {
+		return err
+	}
+
+	wg := sync.WaitGroup{}
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver sends a single delivery and records the outcome.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *repository.DueDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+
+	if err == nil {
+		err = d.Webhooks.MarkDelivered(ctx, delivery.Id, statusCode)
+	} else {
+		var retryAt *time.Time
+		if delivery.Attempts < d.MaxAttempts {
+			next := time.Now().Add(d.Backoff(delivery.Attempts))
+			retryAt = &next
+		}
+		err = d.Webhooks.MarkFailed(ctx, delivery.Id, statusCode, err.Error(), retryAt)
+	}
+
+	if err != nil && ctx.Err() == nil {
+		internal.NewLogger().Log.Error().Err(err).Int64("delivery_id", delivery.Id).Msg("failed to record webhook delivery")
+	}
+}
+
+// send posts the signed payload to the receiver. Any non-2xx response is an
+// error; the status code is returned whenever a response was received.
+func (d *Dispatcher) send(ctx context.Context, delivery *repository.DueDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "FairShare-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	res, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// drain a bounded part of the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// attempts: BaseBackoff doubled per attempt and capped at MaxBackoff.
+func (d *Dispatcher) Backoff(attempts int) time.Duration {
+	delay := d.BaseBackoff
+	for i := 1; i < attempts && delay < d.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, d.MaxBackoff)
+}
+
+// Sign returns the signature sent in the X-FairShare-Signature header: the
+// hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret,
+// prefixed with "sha256=". Receivers recompute it to verify a delivery and
+// reject stale timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature of body sent at
+// timestamp, and timestamp is within tolerance of now.
+func Verify(secret string, timestamp int64, body []byte, signature string, tolerance time.Duration) bool {
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
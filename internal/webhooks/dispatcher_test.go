@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Abdul4code/FairShare/internal/netguard"
+	"github.com/Abdul4code/FairShare/internal/repository"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	testSecret  = "0123456789abcdef"
+	testPayload = `{"group_id":3}`
+)
+
+// received is a request the test receiver got.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a receiver answering every request with status and
+// sending what it receives on the returned channel.
+func newReceiver(t *testing.T, status int) (*httptest.Server, chan received) {
+	t.Helper()
+
+	requests := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+// newTestDispatcher returns a dispatcher backed by a mock database, sending
+// deliveries with client.
+func newTestDispatcher(t *testing.T, client *http.Client) (*Dispatcher, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	d := NewDispatcher(repository.NewModels(db).Webhooks)
+	d.Client = client
+	d.MaxAttempts = 3
+	d.BaseBackoff = time.Minute
+	d.MaxBackoff = time.Hour
+
+	return d, mock
+}
+
+// expectClaim expects the dispatcher to claim a single due delivery.
+func expectClaim(d *Dispatcher, mock sqlmock.Sqlmock, id int64, attempts int, url string) {
+	rows := sqlmock.NewRows([]string{"id", "webhook_id", "event_type", "payload", "attempts", "url", "secret"}).
+		AddRow(id, 3, "group_renamed", []byte(testPayload), attempts, url, testSecret)
+
+	mock.ExpectQuery(`UPDATE webhook_deliveries d`).
+		WithArgs(d.BatchSize, d.LeaseTimeout.Seconds()).
+		WillReturnRows(rows)
+}
+
+// retryAround matches a retry time within a few seconds of want.
+type retryAround struct {
+	want time.Time
+}
+
+func (m retryAround) Match(value driver.Value) bool {
+	at, ok := value.(time.Time)
+	return ok && at.Sub(m.want).Abs() < 5*time.Second
+}
+
+func TestDispatchDueSignsDelivery(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusNoContent)
+	d, mock := newTestDispatcher(t, server.Client())
+
+	expectClaim(d, mock, 7, 1, server.URL)
+	mock.ExpectExec(`SET status = 'delivered'`).
+		WithArgs(int64(7), http.StatusNoContent).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-requests
+
+	if got := string(req.body); got != testPayload {
+		t.Errorf("body = %s, want %s", got, testPayload)
+	}
+	if got := req.header.Get(HeaderEvent); got != "group_renamed" {
+		t.Errorf("%s = %q, want group_renamed", HeaderEvent, got)
+	}
+	if got := req.header.Get(HeaderDelivery); got != "7" {
+		t.Errorf("%s = %q, want 7", HeaderDelivery, got)
+	}
+
+	timestamp, err := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("%s: %v", HeaderTimestamp, err)
+	}
+
+	signature := req.header.Get(HeaderSignature)
+	if !Verify(testSecret, timestamp, req.body, signature, time.Minute) {
+		t.Errorf("%s = %q does not verify", HeaderSignature, signature)
+	}
+	if Verify("another secret!!", timestamp, req.body, signature, time.Minute) {
+		t.Errorf("%s verifies with another secret", HeaderSignature)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDispatchDueSchedulesRetry(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusServiceUnavailable)
+	d, mock := newTestDispatcher(t, server.Client())
+
+	expectClaim(d, mock, 7, 2, server.URL)
+	mock.ExpectExec(`SET status = CASE`).
+		WithArgs(int64(7), http.StatusServiceUnavailable, "receiver responded with status 503", retryAround{time.Now().Add(d.Backoff(2))}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-requests
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDispatchDueGivesUpAfterMaxAttempts(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusInternalServerError)
+	d, mock := newTestDispatcher(t, server.Client())
+
+	expectClaim(d, mock, 7, d.MaxAttempts, server.URL)
+	mock.ExpectExec(`SET status = CASE`).
+		WithArgs(int64(7), http.StatusInternalServerError, "receiver responded with status 500", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-requests
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedeliverSendsCopy(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusOK)
+	d, mock := newTestDispatcher(t, server.Client())
+
+	copied := sqlmock.NewRows([]string{"id", "webhook_id", "event_type", "payload", "status", "attempts",
+		"next_attempt_at", "last_status_code", "last_error", "created_at", "delivered_at"}).
+		AddRow(8, 3, "group_renamed", []byte(testPayload), "pending", 0, "2026-01-01T00:00:00Z", nil, "", "2026-01-01T00:00:00Z", nil)
+
+	mock.ExpectQuery(`INSERT INTO webhook_deliveries`).
+		WithArgs(int64(7), 3).
+		WillReturnRows(copied)
+
+	delivery, err := d.Webhooks.Redeliver(context.Background(), 3, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Id != 8 || delivery.Status != "pending" || delivery.Attempts != 0 {
+		t.Fatalf("Redeliver = %+v, want pending delivery 8 without attempts", delivery)
+	}
+
+	expectClaim(d, mock, delivery.Id, 1, server.URL)
+	mock.ExpectExec(`SET status = 'delivered'`).
+		WithArgs(int64(8), http.StatusOK).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-requests
+	if got := req.header.Get(HeaderDelivery); got != "8" {
+		t.Errorf("%s = %q, want 8", HeaderDelivery, got)
+	}
+	if got := string(req.body); got != testPayload {
+		t.Errorf("body = %s, want %s", got, testPayload)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{BaseBackoff: 30 * time.Second, MaxBackoff: 10 * time.Minute}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := d.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	server, _ := newReceiver(t, http.StatusOK)
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	if !errors.Is(err, netguard.ErrNonPublicAddress) {
+		t.Fatalf("Get(%s) error = %v, want %v", server.URL, err, netguard.ErrNonPublicAddress)
+	}
+}
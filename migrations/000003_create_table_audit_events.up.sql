@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,                       -- auto-incrementing event ID
+    actor_id INT,                                   -- user ID performing the change, NULL when anonymous
+    action VARCHAR(20) NOT NULL,                    -- create | update | delete
+    entity_type VARCHAR(50) NOT NULL,               -- e.g., group
+    entity_id INT NOT NULL,                         -- ID of the changed row
+    request_id VARCHAR(64) NOT NULL DEFAULT '',     -- request that caused the change
+    before JSONB,                                   -- snapshot before the change, NULL on create
+    after JSONB,                                    -- snapshot after the change, NULL on delete
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- time of the change
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_entity_idx ON audit_events (entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at);
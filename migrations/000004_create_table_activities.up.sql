@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS activities (
+    id BIGSERIAL PRIMARY KEY,                       -- auto-incrementing activity ID, doubles as the feed cursor
+    group_id INT NOT NULL,                          -- group the activity belongs to
+    actor_id INT,                                   -- user ID performing the change, NULL when anonymous
+    type VARCHAR(50) NOT NULL,                      -- e.g., group_created, group_renamed
+    message TEXT NOT NULL,                          -- human-readable description
+    changes JSONB NOT NULL DEFAULT '[]',            -- list of changed fields with old and new values
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- time of the activity
+);
+
+CREATE INDEX IF NOT EXISTS activities_group_idx ON activities (group_id, id);
+
+CREATE TABLE IF NOT EXISTS activity_reads (
+    group_id INT NOT NULL,                          -- group whose feed was read
+    user_id INT NOT NULL,                           -- reader
+    last_read_id BIGINT NOT NULL DEFAULT 0,         -- newest activity ID seen by the reader
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- time of the last read
+    PRIMARY KEY (group_id, user_id)
+);
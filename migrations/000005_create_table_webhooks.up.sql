@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,                          -- auto-incrementing integer ID
+    url TEXT NOT NULL,                              -- receiver endpoint
+    secret VARCHAR(128) NOT NULL,                   -- HMAC-SHA256 signing secret
+    event_types TEXT[] NOT NULL DEFAULT '{}',       -- subscribed event types, empty for all events
+    active BOOLEAN NOT NULL DEFAULT TRUE,           -- paused webhooks receive no deliveries
+    created_by INT,                                 -- user ID of creator
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP  -- time of creation
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,                                         -- auto-incrementing delivery ID
+    webhook_id INT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,                                  -- e.g., group_renamed
+    payload JSONB NOT NULL,                                           -- body sent to the receiver
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',                    -- pending | delivered | failed
+    attempts INT NOT NULL DEFAULT 0,                                  -- delivery attempts made so far
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,     -- earliest time of the next attempt
+    last_status_code INT,                                             -- HTTP status of the last attempt
+    last_error TEXT NOT NULL DEFAULT '',                              -- error of the last attempt
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,                   -- time the delivery was queued
+    delivered_at TIMESTAMP                                            -- time of the successful attempt
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, id);
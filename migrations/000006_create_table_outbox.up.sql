@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,                                     -- auto-incrementing message ID, also the publication order
+    event_type VARCHAR(50) NOT NULL,                              -- e.g., group_renamed
+    aggregate_type VARCHAR(50) NOT NULL,                          -- e.g., group
+    aggregate_id INT NOT NULL,                                    -- ID of the changed entity
+    payload JSONB NOT NULL,                                       -- message body handed to every sink
+    attempts INT NOT NULL DEFAULT 0,                              -- failed publication attempts
+    last_error TEXT NOT NULL DEFAULT '',                          -- error of the last failed attempt
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- earliest time of the next attempt
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,               -- time the message was written
+    published_at TIMESTAMP                                        -- time every sink accepted the message
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+-- deliveries created from an outbox message are unique per webhook so a
+-- message published more than once does not deliver twice.
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS outbox_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_outbox_idx ON webhook_deliveries (webhook_id, outbox_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id INT NOT NULL REFERENCES groups (id) ON DELETE CASCADE, -- group the user belongs to
+    user_id INT NOT NULL,                                           -- member user ID
+    role VARCHAR(20) NOT NULL DEFAULT 'member',                     -- owner | member
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,                  -- time the user joined
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS group_members_user_idx ON group_members (user_id);
+
+-- every existing group is owned by its creator
+INSERT INTO group_members (group_id, user_id, role)
+SELECT id, created_by, 'owner' FROM groups
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key TEXT PRIMARY KEY,                                   -- route group and client, e.g. read:user:42
+    tokens DOUBLE PRECISION NOT NULL,                       -- tokens left after the last request
+    allowed BOOLEAN NOT NULL DEFAULT TRUE,                  -- whether the last request was allowed
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP -- time tokens was last computed
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_at_idx ON rate_limit_buckets (updated_at);
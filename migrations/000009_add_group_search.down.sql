@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS groups_description_tsv_idx;
+DROP INDEX IF EXISTS groups_name_trgm_idx;
+DROP INDEX IF EXISTS groups_search_vector_idx;
+ALTER TABLE groups DROP COLUMN IF EXISTS search_vector;
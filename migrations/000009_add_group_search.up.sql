@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- words of the name (weight A) and description (weight B) of a group, stemmed
+-- with the text search config of every supported language, plus unstemmed
+-- words with simple; searches use the config of the request language.
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('french', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('french', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS groups_search_vector_idx ON groups USING GIN (search_vector);
+
+-- trigrams of names, for fuzzy search and the name filter of group listings
+CREATE INDEX IF NOT EXISTS groups_name_trgm_idx ON groups USING GIN (name gin_trgm_ops);
+
+-- the description filter of group listings
+CREATE INDEX IF NOT EXISTS groups_description_tsv_idx ON groups USING GIN (to_tsvector('simple', description));
@@ -0,0 +1,43 @@
+// Package migrations embeds the SQL migrations of the database schema so the
+// binary knows which schema version it expects.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"regexp"
+	"strconv"
+)
+
+// FS holds every migration file of this directory.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// upMigration matches the file name of an up migration, capturing its version.
+var upMigration = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+// Latest returns the version of the newest up migration, which is the schema
+// version the binary was built against.
+func Latest() (int, error) {
+	entries, err := fs.ReadDir(FS, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	latest := 0
+	for _, entry := range entries {
+		match := upMigration.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, err
+		}
+		latest = max(latest, version)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,240 @@
+// Package client is a Go client for the FairShare API.
+//
+//	c, err := client.New("https://api.example.com", client.WithToken(token))
+//	group, err := c.CreateGroup(ctx, client.GroupInput{Name: "Trip", Currency: "Euro"})
+//	if errors.Is(err, client.ErrValidation) { ... }
+//
+// Requests are retried with exponential backoff on 5xx and 429 responses,
+// honoring Retry-After. As the API does not deduplicate writes, POST and PATCH
+// requests are only retried on responses sent before they were processed.
+package client
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client sends requests to the FairShare API. It is safe for concurrent use.
+type Client struct {
+	BaseURL    *url.URL     // API address, e.g. https://api.example.com
+	HTTPClient *http.Client // client sending the requests
+	Token      string       // bearer token identifying the user, anonymous when empty
+	Language   string       // Accept-Language of error messages, the API default when empty
+	UserAgent  string
+
+	MaxRetries int           // retries of a request failing with a 5xx or 429 response
+	MinBackoff time.Duration // wait before the first retry, doubled for each following one
+	MaxBackoff time.Duration // longest wait between retries, including Retry-After
+}
+
+// Option configures a Client created by New.
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with c instead of a client with a 30s timeout.
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) { client.HTTPClient = c }
+}
+
+// WithToken sends the requests on behalf of the user the bearer token was
+// issued to.
+func WithToken(token string) Option {
+	return func(client *Client) { client.Token = token }
+}
+
+// WithLanguage asks for error messages in the language, e.g. fr.
+func WithLanguage(lang string) Option {
+	return func(client *Client) { client.Language = lang }
+}
+
+// WithRetries retries failed requests up to n times, waiting min then twice as
+// long after each attempt, up to max. Zero retries disables retrying.
+func WithRetries(n int, min, max time.Duration) Option {
+	return func(client *Client) {
+		client.MaxRetries = n
+		client.MinBackoff = min
+		client.MaxBackoff = max
+	}
+}
+
+// New returns a client of the API at baseURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("fairshare: invalid base URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("fairshare: invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		BaseURL:    u,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		UserAgent:  "fairshare-go",
+		MaxRetries: 3,
+		MinBackoff: 250 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// NewIdempotencyKey returns a random key identifying a logical write request.
+func NewIdempotencyKey() string {
+	buf := make([]byte, 16)
+	crand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// do sends a request to path, encoding in as its JSON body unless nil, and
+// decodes the JSON response into out unless nil.
+//
+// GET, PUT and DELETE requests are retried on any 5xx or 429 response, while
+// POST and PATCH requests are only retried on 429 and 503 responses, which the
+// rate limiter and load balancers send before the request is processed: the
+// API does not deduplicate writes, so retrying them after other failures could
+// apply them twice. Write requests still carry an Idempotency-Key, the same for
+// every attempt, which proxies and logs may use to correlate the attempts.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("fairshare: encode request: %w", err)
+		}
+	}
+
+	u := c.BaseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	idempotencyKey := ""
+	if method != http.MethodGet {
+		idempotencyKey = NewIdempotencyKey()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := c.newRequest(ctx, method, u, body, idempotencyKey)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("fairshare: %w", err)
+		}
+
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("fairshare: read response: %w", err)
+		}
+
+		if res.StatusCode < 300 {
+			if out == nil {
+				return nil
+			}
+			if err := json.Unmarshal(data, out); err != nil {
+				return fmt.Errorf("fairshare: decode response: %w", err)
+			}
+			return nil
+		}
+
+		apiErr := newAPIError(res, data)
+		if attempt >= c.MaxRetries || !retryable(method, res.StatusCode) {
+			return apiErr
+		}
+
+		if err := sleep(ctx, c.backoff(attempt, res.Header.Get("Retry-After"))); err != nil {
+			return errors.Join(apiErr, err)
+		}
+	}
+}
+
+// newRequest returns a request to u with the headers of the client, sending body
+// as JSON unless nil.
+func (c *Client) newRequest(ctx context.Context, method string, u *url.URL, body []byte, idempotencyKey string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("fairshare: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Language != "" {
+		req.Header.Set("Accept-Language", c.Language)
+	}
+
+	return req, nil
+}
+
+// retryable reports whether a request with the method may be sent again after
+// a response with the status.
+func retryable(method string, status int) bool {
+	switch method {
+	case http.MethodPost, http.MethodPatch:
+		return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+	default:
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+}
+
+// backoff returns how long to wait before retrying after the attempt: the
+// Retry-After delay when the response has one, otherwise an exponential delay
+// with jitter. Both are capped at MaxBackoff.
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return min(time.Duration(seconds)*time.Second, c.MaxBackoff)
+	}
+
+	if t, err := http.ParseTime(retryAfter); err == nil {
+		return min(max(time.Until(t), 0), c.MaxBackoff)
+	}
+
+	wait := c.MaxBackoff
+	if attempt < 30 {
+		wait = min(c.MinBackoff<<attempt, c.MaxBackoff)
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// sleep waits for d, or returns the error of ctx when it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a fake API answering requests with the statuses of responses in
+// turn, then with a group, and recording the requests it gets.
+type recorder struct {
+	mu        sync.Mutex
+	responses []int
+	requests  []*http.Request
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.requests = append(rec.requests, r)
+
+	if len(rec.responses) > 0 {
+		status := rec.responses[0]
+		rec.responses = rec.responses[1:]
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"failed"}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"id":3,"name":"Trip","currency":"Euro","version":1}`))
+}
+
+// newTestClient returns a client of a fake API answering with the statuses of
+// responses before succeeding, retrying up to twice without waiting.
+func newTestClient(t *testing.T, responses ...int) (*Client, *recorder) {
+	t.Helper()
+
+	rec := &recorder{responses: responses}
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithToken("token"), WithRetries(2, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c, rec
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		responses []int
+		attempts  int
+		want      error
+	}{
+		{"GET after 429", http.MethodGet, []int{429}, 2, nil},
+		{"GET after 500 and 502", http.MethodGet, []int{500, 502}, 3, nil},
+		{"GET gives up", http.MethodGet, []int{500, 500, 500}, 3, ErrServer},
+		{"GET not on 404", http.MethodGet, []int{404}, 1, ErrNotFound},
+		{"POST after 429 and 503", http.MethodPost, []int{429, 503}, 3, nil},
+		{"POST not on 500", http.MethodPost, []int{500}, 1, ErrServer},
+		{"PATCH not on 502", http.MethodPatch, []int{502}, 1, ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newTestClient(t, tt.responses...)
+
+			err := c.do(context.Background(), tt.method, "/v1/groups/3", nil, nil, &Group{})
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if len(rec.requests) != tt.attempts {
+				t.Errorf("sent %d requests, want %d", len(rec.requests), tt.attempts)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	c, rec := newTestClient(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+
+	if _, err := c.CreateGroup(context.Background(), GroupInput{Name: "Trip", Currency: "Euro"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetGroup(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.requests) != 4 {
+		t.Fatalf("sent %d requests, want 4", len(rec.requests))
+	}
+
+	// every attempt of the write carries the same key
+	key := rec.requests[0].Header.Get("Idempotency-Key")
+	if key == "" {
+		t.Fatal("POST has no Idempotency-Key")
+	}
+	for i, req := range rec.requests[1:3] {
+		if got := req.Header.Get("Idempotency-Key"); got != key {
+			t.Errorf("retry %d has Idempotency-Key %q, want %q", i+1, got, key)
+		}
+	}
+
+	if got := rec.requests[3].Header.Get("Idempotency-Key"); got != "" {
+		t.Errorf("GET has Idempotency-Key %q", got)
+	}
+
+	for _, req := range rec.requests {
+		if got := req.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("%s has Authorization %q, want Bearer token", req.Method, got)
+		}
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   error
+		others []error
+	}{
+		{http.StatusNotFound, `{"error":"the requested resource could not be found"}`, ErrNotFound, []error{ErrConflict, ErrValidation}},
+		{http.StatusConflict, `{"error":"edit conflict"}`, ErrConflict, []error{ErrNotFound, ErrValidation}},
+		{http.StatusUnprocessableEntity, `{"error":{"currency":"must be a supported currency"}}`, ErrValidation, []error{ErrNotFound, ErrConflict}},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-1")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			c, err := New(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.GetGroup(context.Background(), 3)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			for _, other := range tt.others {
+				if errors.Is(err, other) {
+					t.Errorf("error %v matches %v", err, other)
+				}
+			}
+
+			apiErr := &APIError{}
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %T, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.RequestId != "req-1" {
+				t.Errorf("APIError = %+v, want status %d of request req-1", apiErr, tt.status)
+			}
+			if tt.want == ErrValidation && apiErr.Fields["currency"] != "must be a supported currency" {
+				t.Errorf("Fields = %v, want a message for currency", apiErr.Fields)
+			}
+			if tt.want != ErrValidation && apiErr.Message == "" {
+				t.Error("Message is empty")
+			}
+		})
+	}
+}
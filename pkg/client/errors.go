@@ -0,0 +1,83 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors matched by the errors.Is method of *APIError, by response status.
+var (
+	ErrValidation   = errors.New("fairshare: invalid request")         // 400 and 422
+	ErrUnauthorized = errors.New("fairshare: authentication required") // 401
+	ErrNotFound     = errors.New("fairshare: not found")               // 404
+	ErrConflict     = errors.New("fairshare: conflict")                // 409, e.g. an edit conflict
+	ErrRateLimited  = errors.New("fairshare: rate limit exceeded")     // 429
+	ErrServer       = errors.New("fairshare: server error")            // 5xx
+)
+
+// APIError is returned for responses with an error status. Use errors.Is with
+// the Err variables to test for a kind of error, and Fields for the message of
+// each invalid field of a validation error.
+type APIError struct {
+	StatusCode int
+	Message    string            // message of the error, empty for validation errors
+	Fields     map[string]string // message per invalid field of validation errors
+	RequestId  string            // X-Request-Id of the failed request, to quote in bug reports
+}
+
+// Error returns the status and message of the error.
+func (e *APIError) Error() string {
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("fairshare: %d %s: %v", e.StatusCode, http.StatusText(e.StatusCode), e.Fields)
+	}
+	return fmt.Sprintf("fairshare: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is reports whether the error is of the kind of target, one of the Err variables.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError reads the {"error": ...} body of an error response, whose error
+// is either a message or a message per invalid field.
+func newAPIError(res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestId:  res.Header.Get("X-Request-Id"),
+	}
+
+	envelope := struct {
+		Error json.RawMessage `json:"error"`
+	}{}
+
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Error) == 0 {
+		apiErr.Message = http.StatusText(res.StatusCode)
+		return apiErr
+	}
+
+	if err := json.Unmarshal(envelope.Error, &apiErr.Message); err == nil {
+		return apiErr
+	}
+
+	if err := json.Unmarshal(envelope.Error, &apiErr.Fields); err != nil {
+		apiErr.Message = string(envelope.Error)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GroupEvents streams the activities of the group identified by id as they
+// happen. When lastEventId is not negative, the activities after it are
+// received first, so a consumer can resume from the last activity it handled.
+//
+// The stream ends when ctx is done, when the group is deleted or when the
+// server closes the connection, for example while shutting down. Iteration
+// stops after the first error, which is yielded with a nil activity.
+func (c *Client) GroupEvents(ctx context.Context, id int, lastEventId int64) iter.Seq2[*Activity, error] {
+	return func(yield func(*Activity, error) bool) {
+		req, err := c.newRequest(ctx, http.MethodGet, c.BaseURL.JoinPath(groupPath(id), "events"), nil, "")
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		req.Header.Set("Accept", "text/event-stream")
+		if lastEventId >= 0 {
+			req.Header.Set("Last-Event-ID", strconv.FormatInt(lastEventId, 10))
+		}
+
+		// the timeout of the client would end the stream
+		stream := *c.HTTPClient
+		stream.Timeout = 0
+
+		res, err := stream.Do(req)
+		if err != nil {
+			yield(nil, fmt.Errorf("fairshare: %w", err))
+			return
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			data, _ := io.ReadAll(res.Body)
+			yield(nil, newAPIError(res, data))
+			return
+		}
+
+		scanner := bufio.NewScanner(res.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+		data := strings.Builder{}
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case line == "":
+				// a blank line dispatches the event; heartbeats carry no data
+				if data.Len() == 0 {
+					continue
+				}
+
+				activity := &Activity{}
+				err := json.Unmarshal([]byte(data.String()), activity)
+				data.Reset()
+				if err != nil {
+					yield(nil, fmt.Errorf("fairshare: decode event: %w", err))
+					return
+				}
+
+				if !yield(activity, nil) {
+					return
+				}
+			case strings.HasPrefix(line, "data:"):
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+		}
+
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			yield(nil, fmt.Errorf("fairshare: read events: %w", err))
+		}
+	}
+}
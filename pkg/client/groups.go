@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CreateGroup creates a group and returns it.
+func (c *Client) CreateGroup(ctx context.Context, input GroupInput) (*Group, error) {
+	group := &Group{}
+	if err := c.do(ctx, http.MethodPost, "/v1/groups", nil, input, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetGroup returns the group identified by id, or an error matching ErrNotFound.
+func (c *Client) GetGroup(ctx context.Context, id int) (*Group, error) {
+	group := &Group{}
+	if err := c.do(ctx, http.MethodGet, groupPath(id), nil, nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// PatchGroup updates the non-nil fields of input on the group identified by id
+// and returns the updated group. It returns an error matching ErrConflict when
+// the group was modified concurrently.
+func (c *Client) PatchGroup(ctx context.Context, id int, input GroupUpdate) (*Group, error) {
+	group := &Group{}
+	if err := c.do(ctx, http.MethodPatch, groupPath(id), nil, input, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes the group identified by id.
+func (c *Client) DeleteGroup(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, groupPath(id), nil, nil, nil)
+}
+
+// ListGroups returns the page of groups described by params.
+func (c *Client) ListGroups(ctx context.Context, params ListGroupsParams) (*GroupPage, error) {
+	query := url.Values{}
+	setQuery(query, "name", params.Name)
+	setQuery(query, "currency", params.Currency)
+	setQuery(query, "description", params.Description)
+	setQuery(query, "filter", params.Filter)
+	setQuery(query, "sort", params.Sort)
+	if params.Page > 0 {
+		query.Set("page", strconv.Itoa(params.Page))
+	}
+	if params.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(params.PageSize))
+	}
+
+	page := &GroupPage{}
+	if err := c.do(ctx, http.MethodGet, "/v1/groups", query, nil, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Groups iterates over the groups matching params, fetching one page at a time
+// from params.Page, or the first page when zero. Iteration stops after the
+// first error, which is yielded with a nil group.
+//
+//	for group, err := range c.Groups(ctx, client.ListGroupsParams{Currency: "Euro"}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Groups(ctx context.Context, params ListGroupsParams) iter.Seq2[*Group, error] {
+	return func(yield func(*Group, error) bool) {
+		if params.Page < 1 {
+			params.Page = 1
+		}
+
+		for {
+			page, err := c.ListGroups(ctx, params)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, group := range page.Data {
+				if !yield(group, nil) {
+					return
+				}
+			}
+
+			if len(page.Data) == 0 || page.Metadata.CurrentPage >= page.Metadata.LastPage {
+				return
+			}
+			params.Page = page.Metadata.CurrentPage + 1
+		}
+	}
+}
+
+// groupPath returns the path of the group identified by id.
+func groupPath(id int) string {
+	return "/v1/groups/" + strconv.Itoa(id)
+}
+
+// setQuery sets the query parameter unless value is empty.
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+// UpdateGroup replaces the name, currency and description of the group
+// identified by id and returns the updated group.
+func (c *Client) UpdateGroup(ctx context.Context, id int, input GroupInput) (*Group, error) {
+	group := &Group{}
+	if err := c.do(ctx, http.MethodPut, groupPath(id), nil, input, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GroupHistory returns a page of the audit events of the group identified by
+// id, newest first. The history remains available after the group is deleted.
+func (c *Client) GroupHistory(ctx context.Context, id int, params HistoryParams) (*AuditPage, error) {
+	query := url.Values{}
+	if !params.From.IsZero() {
+		query.Set("from", params.From.Format(time.RFC3339))
+	}
+	if !params.To.IsZero() {
+		query.Set("to", params.To.Format(time.RFC3339))
+	}
+	if params.Page > 0 {
+		query.Set("page", strconv.Itoa(params.Page))
+	}
+	if params.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(params.PageSize))
+	}
+
+	page := &AuditPage{}
+	if err := c.do(ctx, http.MethodGet, groupPath(id)+"/history", query, nil, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GroupActivity returns a page of the activity feed of the group identified by
+// id, newest first.
+func (c *Client) GroupActivity(ctx context.Context, id int, params ActivityParams) (*ActivityPage, error) {
+	query := url.Values{}
+	if params.Cursor > 0 {
+		query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	page := &ActivityPage{}
+	if err := c.do(ctx, http.MethodGet, groupPath(id)+"/activity", query, nil, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// MarkActivityRead marks the activity feed of the group identified by id as
+// read up to lastReadId, or entirely when nil, and returns the new marker. It
+// requires a user.
+func (c *Client) MarkActivityRead(ctx context.Context, id int, lastReadId *int64) (int64, error) {
+	input := struct {
+		LastReadId *int64 `json:"last_read_id"`
+	}{lastReadId}
+
+	marker := struct {
+		LastReadId int64 `json:"last_read_id"`
+	}{}
+
+	if err := c.do(ctx, http.MethodPost, groupPath(id)+"/activity/read", nil, input, &marker); err != nil {
+		return 0, err
+	}
+	return marker.LastReadId, nil
+}
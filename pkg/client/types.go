@@ -0,0 +1,136 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Group is a group as returned by the API.
+type Group struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	CreatedBy   int    `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	Version     int    `json:"version"`
+}
+
+// GroupInput is the payload used to create or replace a group.
+type GroupInput struct {
+	Name        string `json:"name"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	CreatedBy   int    `json:"created_by"`
+}
+
+// GroupUpdate is the payload used to update some fields of a group. Nil
+// fields are left unchanged.
+type GroupUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Currency    *string `json:"currency,omitempty"`
+	Description *string `json:"description,omitempty"`
+	CreatedBy   *int    `json:"created_by,omitempty"`
+}
+
+// ListGroupsParams filters, sorts and paginates the groups returned by
+// ListGroups. Zero fields use the defaults of the API.
+type ListGroupsParams struct {
+	Name        string // groups whose name contains Name
+	Currency    string // groups using Currency, or one of a comma separated list, e.g. Euro,Pound
+	Description string // groups whose description matches the words of Description
+	Filter      string // filter expression, e.g. currency in (Euro, Pound) and created_at >= 2025-01-01
+	Sort        string // comma separated fields to sort by, prefixed with - for descending order, e.g. currency,-created_at
+	Page        int
+	PageSize    int
+}
+
+// MetaData describes the page of a list response.
+type MetaData struct {
+	CurrentPage int `json:"current_page"`
+	LastPage    int `json:"last_page"`
+	PageSize    int `json:"page_size"`
+	Total       int `json:"total"`
+}
+
+// GroupPage is a page of groups returned by ListGroups.
+type GroupPage struct {
+	Metadata MetaData `json:"metadata"`
+	Data     []*Group `json:"data"`
+}
+
+// AuditEvent is an entry of the audit log recording a change to an entity.
+type AuditEvent struct {
+	Id         int64           `json:"id"`
+	ActorId    *int            `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityId   int             `json:"entity_id"`
+	RequestId  string          `json:"request_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// HistoryParams filters and paginates the audit events returned by GroupHistory.
+type HistoryParams struct {
+	From     time.Time // events created at or after From, unless zero
+	To       time.Time // events created before To, unless zero
+	Page     int
+	PageSize int
+}
+
+// AuditPage is a page of audit events returned by GroupHistory.
+type AuditPage struct {
+	Metadata MetaData      `json:"metadata"`
+	Data     []*AuditEvent `json:"data"`
+}
+
+// Activity is an entry of the activity feed of a group.
+type Activity struct {
+	Id        int64         `json:"id"`
+	GroupId   int           `json:"group_id"`
+	ActorId   *int          `json:"actor_id"`
+	Type      string        `json:"type"`
+	Message   string        `json:"message"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt string        `json:"created_at"`
+}
+
+// FieldChange describes a field whose value changed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// ActivityParams paginates the activity feed returned by GroupActivity. Cursor
+// is the id of the last activity already seen; zero starts from the newest.
+type ActivityParams struct {
+	Cursor int64
+	Limit  int
+}
+
+// ActivityMetaData holds the cursor and unread information of a feed page.
+type ActivityMetaData struct {
+	NextCursor  *int64 `json:"next_cursor"`
+	Limit       int    `json:"limit"`
+	LastReadId  int64  `json:"last_read_id"`
+	UnreadCount int    `json:"unread_count"`
+}
+
+// ActivityPage is a page of the activity feed returned by GroupActivity.
+type ActivityPage struct {
+	Metadata ActivityMetaData `json:"metadata"`
+	Data     []*Activity      `json:"data"`
+}
+
+// String returns a pointer to s, for the fields of GroupUpdate.
+func String(s string) *string {
+	return &s
+}
+
+// Int returns a pointer to i, for the fields of GroupUpdate.
+func Int(i int) *int {
+	return &i
+}